@@ -129,7 +129,7 @@ func LoggingAndRecovery(color bool) pure.Middleware {
 					color = status300
 				}
 
-				log.Printf("%s %d %s[%s%s%s] %q %v %d\n", color, code, ansi.Reset, color, r.Method, ansi.Reset, r.URL, time.Since(t1), lw.Size())
+				log.Printf("%s %d %s[%s%s%s] %q %v %d\n", color, code, ansi.Reset, color, r.Method, ansi.Reset, route(r), time.Since(t1), lw.Size())
 			}
 		}
 
@@ -156,12 +156,21 @@ func LoggingAndRecovery(color bool) pure.Middleware {
 
 			next(lw, r)
 
-			log.Printf("%d [%s] %q %v %d\n", lw.Status(), r.Method, r.URL, time.Since(t1), lw.Size())
+			log.Printf("%d [%s] %q %v %d\n", lw.Status(), r.Method, route(r), time.Since(t1), lw.Size())
 		}
 
 	}
 }
 
+// route returns the matched route pattern eg. /users/:id to keep log cardinality low,
+// falling back to the requested path when no route was matched
+func route(r *http.Request) string {
+	if pattern := pure.RequestVars(r).Pattern(); pattern != "" {
+		return pattern
+	}
+	return r.URL.Path
+}
+
 // HandlePanic handles graceful panic by redirecting to friendly error page or rendering a friendly error page.
 // trace passed just in case you want rendered to developer when not running in production
 func HandlePanic(w http.ResponseWriter, r *http.Request, trace []byte) {
@@ -10,4 +10,7 @@ const (
 	slashByte     = '/'
 	paramByte     = ':'
 	wildByte      = '*'
+
+	constraintStartByte = '<'
+	constraintEndByte   = '>'
 )
@@ -0,0 +1,96 @@
+package pure
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// Constraint validates a URL param value, returning false when the
+// value does not satisfy the constraint.
+type Constraint func(value string) bool
+
+// default constraints available to all routes eg. /users/:id<int>
+var defaultConstraints = map[string]Constraint{
+	"int":      isInt,
+	"uint":     isUint,
+	"uuid":     isUUID,
+	"alpha":    isAlpha,
+	"alphanum": isAlphanumeric,
+}
+
+// RegisterConstraint registers a named constraint that can be used in route
+// patterns eg. p.RegisterConstraint("hex", fn) allows /colors/:code<hex>
+//
+// NOTE: constraints must be registered prior to registering the routes that use them.
+func (p *Mux) RegisterConstraint(name string, c Constraint) {
+	p.constraints[name] = c
+}
+
+// constraint returns the named constraint or when not a registered name
+// compiles the constraint as a regular expression matching the whole value.
+func (p *Mux) constraint(c string, path string) (Constraint, error) {
+	if fn, ok := p.constraints[c]; ok {
+		return fn, nil
+	}
+	re, err := regexp.Compile("^(?:" + c + ")$")
+	if err != nil {
+		return nil, &InvalidPathError{Path: path, Reason: "Invalid constraint '<" + c + ">'", Err: err}
+	}
+	return re.MatchString, nil
+}
+
+// isUint returns if the value is a base 10 unsigned integer that fits in a uint64
+func isUint(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+// isInt returns if the value is a base 10 signed integer that fits in an int64
+func isInt(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
+func isAlpha(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c|0x20 < 'a' || c|0x20 > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if (c < '0' || c > '9') && (c|0x20 < 'a' || c|0x20 > 'f') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package pure
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestConstraints(t *testing.T) {
+
+	paramsHandler := func(w http.ResponseWriter, r *http.Request) {
+		rv := RequestVars(r)
+		if _, err := w.Write([]byte(rv.URLParam("id") + "|" + rv.URLParam("slug") + "|" + rv.URLParam("uuid") + "|" + rv.URLParam("time"))); err != nil {
+			panic(err)
+		}
+	}
+
+	p := New()
+	p.RegisterMethodNotAllowed()
+	p.RegisterConstraint("even", func(value string) bool {
+		return isUint(value) && (value[len(value)-1]-'0')%2 == 0
+	})
+
+	p.Get("/users/:id<int>", paramsHandler)
+	p.Get("/users/:id<int>/profile", paramsHandler)
+	p.Get("/posts/:slug<[a-z0-9-]+>", paramsHandler)
+	p.Get("/files/:uuid<uuid>", paramsHandler)
+	p.Get("/evens/:id<even>", paramsHandler)
+	p.Get("/at/:time<\\d{2}:\\d{2}>/*", paramsHandler)
+	p.Post("/posts/:slug", paramsHandler)
+
+	tests := []struct {
+		method string
+		url    string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/users/13", http.StatusOK, "13|||"},
+		{http.MethodGet, "/users/-13", http.StatusOK, "-13|||"},
+		{http.MethodGet, "/users/joeybloggs", http.StatusNotFound, ""},
+		{http.MethodGet, "/users/13/profile", http.StatusOK, "13|||"},
+		{http.MethodGet, "/users/joeybloggs/profile", http.StatusNotFound, ""},
+		{http.MethodGet, "/posts/my-first-post-2", http.StatusOK, "|my-first-post-2||"},
+		{http.MethodGet, "/posts/My_Post", http.StatusMethodNotAllowed, ""},
+		{http.MethodPost, "/posts/My_Post", http.StatusOK, "|My_Post||"},
+		{http.MethodGet, "/files/0b7e2a1c-1b5e-4c1e-9f4e-1f0e7d8e6a3b", http.StatusOK, "||0b7e2a1c-1b5e-4c1e-9f4e-1f0e7d8e6a3b|"},
+		{http.MethodGet, "/files/0b7e2a1c-1b5e-4c1e-9f4e-1f0e7d8e6a3", http.StatusNotFound, ""},
+		{http.MethodGet, "/files/0b7e2a1cx1b5e-4c1e-9f4e-1f0e7d8e6a3b", http.StatusNotFound, ""},
+		{http.MethodGet, "/evens/12", http.StatusOK, "12|||"},
+		{http.MethodGet, "/evens/13", http.StatusNotFound, ""},
+		{http.MethodGet, "/at/10:30/anything", http.StatusOK, "|||10:30"},
+		{http.MethodGet, "/at/1030/anything", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		code, body := request(tt.method, tt.url, p)
+		Equal(t, code, tt.code)
+		if tt.code == http.StatusOK {
+			Equal(t, body, tt.body)
+		}
+	}
+
+	p.Get("/named/:id<int>/:slug<[a-z]+>", paramsHandler).Name("named")
+	u, err := p.URL("named", "id", "1", "slug", "abc")
+	Equal(t, err, nil)
+	Equal(t, u, "/named/1/abc")
+}
+
+func TestBadConstraints(t *testing.T) {
+
+	p := New()
+	PanicMatches(t, func() { p.Get("/users/:id<[a-z>", defaultHandler) }, "Invalid constraint '<[a-z>' in path '/users/:id<[a-z>': error parsing regexp: missing closing ]: `[a-z)$`")
+	PanicMatches(t, func() { p.Get("/users/:id<int", defaultHandler) }, "unterminated constraint in path '/users/:id<int'")
+	PanicMatches(t, func() { p.Get("/users/:<int>", defaultHandler) }, "wildcards must be named with a non-empty name in path '/users/:<int>'")
+
+	p.Get("/accounts/:id<int>", defaultHandler)
+	PanicMatches(t, func() { p.Get("/accounts/:id", defaultHandler) }, "path segment ':id' conflicts with existing wildcard ':id<int>' in path '/accounts/:id'")
+	PanicMatches(t, func() { p.Get("/accounts/:id<int>/:id<uuid>", defaultHandler) }, "Duplicate param name ':id' detected for route '/accounts/:id<int>/:id<uuid>'")
+}
+
+func TestDefaultConstraints(t *testing.T) {
+
+	tests := []struct {
+		constraint string
+		value      string
+		expected   bool
+	}{
+		{"int", "10", true},
+		{"int", "+10", true},
+		{"int", "-", false},
+		{"int", "", false},
+		{"int", "1.0", false},
+		{"int", "9223372036854775807", true},
+		{"int", "-9223372036854775808", true},
+		{"int", "9223372036854775808", false},
+		{"int", "-9223372036854775809", false},
+		{"int", "99999999999999999999999999", false},
+		{"uint", "10", true},
+		{"uint", "-10", false},
+		{"uint", "+10", false},
+		{"uint", "", false},
+		{"uint", "18446744073709551615", true},
+		{"uint", "18446744073709551616", false},
+		{"alpha", "abcXYZ", true},
+		{"alpha", "abc1", false},
+		{"alpha", "", false},
+		{"alphanum", "abc1XYZ", true},
+		{"alphanum", "abc-1", false},
+		{"alphanum", "", false},
+		{"uuid", "0B7E2A1C-1B5E-4C1E-9F4E-1F0E7D8E6A3B", true},
+		{"uuid", "0b7e2a1c-1b5e-4c1e-9f4e-1f0e7d8e6a3g", false},
+	}
+
+	for _, tt := range tests {
+		Equal(t, defaultConstraints[tt.constraint](tt.value), tt.expected)
+	}
+}
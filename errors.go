@@ -0,0 +1,118 @@
+package pure
+
+import (
+	"strconv"
+)
+
+// pathFormat is how the path is included in an error's message, those
+// of some errors keeping the wording they were originally panicked with
+type pathFormat uint8
+
+const (
+	inPath    pathFormat = iota // <reason> in path '<path>'
+	onPath                      // <reason> on path '<path>'
+	commaPath                   // <reason>, path '<path>'
+	noPath                      // <reason>
+)
+
+func (f pathFormat) format(reason string, path string) string {
+	switch f {
+	case onPath:
+		return reason + " on path '" + path + "'"
+	case commaPath:
+		return reason + ", path '" + path + "'"
+	case noPath:
+		return reason
+	default:
+		return reason + " in path '" + path + "'"
+	}
+}
+
+// DoubleSlashError is returned when registering a path containing a duplicate //
+type DoubleSlashError struct {
+	Path  string
+	Index int
+}
+
+func (e *DoubleSlashError) Error() string {
+	return "Bad path '" + e.Path + "' contains duplicate // at index:" + strconv.Itoa(e.Index)
+}
+
+// DuplicateRouteError is returned when a handler is already registered
+// for the method and path
+type DuplicateRouteError struct {
+	Method string
+	Path   string
+}
+
+func (e *DuplicateRouteError) Error() string {
+	return "handlers are already registered for path '" + e.Path + "'"
+}
+
+// DuplicateParamError is returned when the same param name is used more
+// than once within a path, params and catch-alls sharing the same names
+type DuplicateParamError struct {
+	Path  string
+	Param string
+}
+
+func (e *DuplicateParamError) Error() string {
+	return "Duplicate param name '" + e.Param + "' detected for route '" + e.Path + "'"
+}
+
+// DuplicateNameError is returned when a route name is already
+// registered for a different path
+type DuplicateNameError struct {
+	Name string
+	Path string // path the name is registered for
+}
+
+func (e *DuplicateNameError) Error() string {
+	return "Route name '" + e.Name + "' is already registered for path '" + e.Path + "'"
+}
+
+// WildcardConflictError is returned when a path segment conflicts with
+// a different param or catch-all already registered at the same position
+type WildcardConflictError struct {
+	Path     string
+	Segment  string
+	Existing string
+}
+
+func (e *WildcardConflictError) Error() string {
+	return "path segment '" + e.Segment + "' conflicts with existing wildcard '" + e.Existing + "' in path '" + e.Path + "'"
+}
+
+// CatchAllError is returned when a catch-all is not positioned correctly
+// within the path
+type CatchAllError struct {
+	Path   string
+	Reason string
+	format pathFormat
+}
+
+func (e *CatchAllError) Error() string {
+	return e.format.format(e.Reason, e.Path)
+}
+
+// InvalidPathError is returned when a path can not be parsed eg. a param
+// without a name or an invalid constraint
+type InvalidPathError struct {
+	Path   string
+	Reason string
+	Err    error // underlying error, if any
+	format pathFormat
+}
+
+func (e *InvalidPathError) Error() string {
+	s := e.format.format(e.Reason, e.Path)
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// Unwrap returns the underlying error, if any
+func (e *InvalidPathError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,97 @@
+package pure
+
+import (
+	"errors"
+	"net/http"
+	"regexp/syntax"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestTryHandle(t *testing.T) {
+
+	p := New()
+	p.Get("/users/:id", idHandler)
+	p.Get("/files/*", defaultHandler)
+
+	r, err := p.TryHandle(http.MethodPost, "/users/:id", idHandler)
+	Equal(t, err, nil)
+	Equal(t, r.Pattern(), "/users/:id")
+
+	_, err = p.TryHandle(http.MethodGet, "/users//:id", idHandler)
+	var dse *DoubleSlashError
+	Equal(t, errors.As(err, &dse), true)
+	Equal(t, dse.Index, 6)
+	Equal(t, err.Error(), "Bad path '/users//:id' contains duplicate // at index:6")
+
+	_, err = p.TryHandle(http.MethodGet, "/users/:id", idHandler)
+	var dre *DuplicateRouteError
+	Equal(t, errors.As(err, &dre), true)
+	Equal(t, dre.Method, http.MethodGet)
+	Equal(t, dre.Path, "/users/:id")
+
+	_, err = p.TryHandle(http.MethodGet, "/users/:user_id/profile", idHandler)
+	var wce *WildcardConflictError
+	Equal(t, errors.As(err, &wce), true)
+	Equal(t, wce.Segment, ":user_id/profile")
+	Equal(t, wce.Existing, ":id")
+
+	_, err = p.TryHandle(http.MethodGet, "/files/*.css", defaultHandler)
+	var cae *CatchAllError
+	Equal(t, errors.As(err, &cae), true)
+	Equal(t, cae.Reason, "Character after the * symbol is not permitted")
+
+	_, err = p.TryHandle(http.MethodGet, "/accounts/:id/*id", idHandler)
+	var dpe *DuplicateParamError
+	Equal(t, errors.As(err, &dpe), true)
+	Equal(t, dpe.Param, "*id")
+
+	_, err = p.TryHandle(http.MethodGet, "/accounts/:id<[a-z>", idHandler)
+	var ipe *InvalidPathError
+	Equal(t, errors.As(err, &ipe), true)
+	Equal(t, ipe.Reason, "Invalid constraint '<[a-z>'")
+	var se *syntax.Error
+	Equal(t, errors.As(err, &se), true)
+
+	// failed registrations must leave the routes unchanged
+	_, err = p.TryHandle(http.MethodGet, "/accounts/:id/*path/:path", idHandler)
+	Equal(t, errors.As(err, &dpe), true)
+	_, err = p.TryHandle(http.MethodPut, "/accounts/:id<[a-z>", idHandler)
+	Equal(t, errors.As(err, &ipe), true)
+
+	p.Get("/accounts/:account", defaultHandler)
+	Equal(t, len(p.Routes()), 4)
+
+	tests := []struct {
+		method string
+		url    string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/users/13", http.StatusOK, "13"},
+		{http.MethodPost, "/users/13", http.StatusOK, "13"},
+		{http.MethodGet, "/accounts/13", http.StatusOK, http.MethodGet},
+		{http.MethodGet, "/accounts/13/a/b", http.StatusNotFound, ""},
+		{http.MethodPut, "/accounts/13", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		code, body := request(tt.method, tt.url, p)
+		Equal(t, code, tt.code)
+		if tt.code == http.StatusOK {
+			Equal(t, body, tt.body)
+		}
+	}
+}
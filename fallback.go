@@ -0,0 +1,82 @@
+package pure
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Register404 registers the not found handler for unmatched paths beneath the group's
+// prefix, taking precedence over the Mux's, eg. so /api can respond with a JSON problem
+// document while the rest of the site responds with an HTML page. When groups are nested
+// the handler of the longest matching prefix is used.
+//
+// NOTE: like Mux.Register404 the group's middleware is not applied, only that provided.
+func (g *routeGroup) Register404(notFound http.HandlerFunc, middleware ...Middleware) {
+	g.registerFallback(g.pure.table.notFound, notFound, middleware)
+}
+
+// Register405 registers the method not allowed handler for paths beneath the group's
+// prefix matching a route, but not for the requested method, and enables handling the
+// 405 Method Not Allowed status code for them. It takes precedence over the Mux's handler
+// registered using RegisterMethodNotAllowed and the allowed methods are available using
+// RequestVars(r).AllowedMethods(). When groups are nested the handler of the longest
+// matching prefix is used.
+//
+// NOTE: like Mux.RegisterMethodNotAllowed the group's middleware is not applied, only that provided.
+func (g *routeGroup) Register405(methodNotAllowed http.HandlerFunc, middleware ...Middleware) {
+	g.registerFallback(g.pure.table.methodNotAllowed, methodNotAllowed, middleware)
+}
+
+// registerFallback registers the handler for the group's prefix, and every path beneath
+// it, in the host's fallback tree; replacing any registered for the same prefix.
+func (g *routeGroup) registerFallback(trees map[string]*node, handler http.HandlerFunc, middleware []Middleware) {
+	h := handler
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+
+	prefix := strings.TrimSuffix(g.prefix, basePath)
+
+	// a catch-all also matches the empty path so the bare prefix is only
+	// registered separately when it isn't the root
+	patterns := []string{prefix + "/*"}
+	if prefix != blank {
+		patterns = append(patterns, prefix)
+	}
+
+	tree := trees[g.host]
+	if tree == nil {
+		tree = new(node)
+	}
+
+	for _, pattern := range patterns {
+		t, _, err := tree.add(pattern, h, g.pure)
+		if _, ok := err.(*DuplicateRouteError); ok {
+			path, _ := g.pure.routePath(pattern)
+			t, _ = tree.modify(path, func(leaf *node) {
+				leaf.handler = h
+			})
+		} else if err != nil {
+			panic(err)
+		}
+		tree = t
+	}
+	trees[g.host] = tree
+}
+
+// fallback returns the handler registered for the longest group prefix matching
+// the path in the host's fallback tree, if any
+func (p *Mux) fallback(trees map[string]*node, host string, path string) http.HandlerFunc {
+	tree := trees[host]
+	if tree == nil {
+		return nil
+	}
+	leaf, rv := tree.find(path, nil, p)
+	if rv != nil {
+		p.pool.Put(rv)
+	}
+	if leaf == nil {
+		return nil
+	}
+	return leaf.handle()
+}
@@ -0,0 +1,120 @@
+package pure
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestGroupFallbacks(t *testing.T) {
+	respond := func(status int, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, body)
+		}
+	}
+
+	p := New()
+	p.Register404(respond(http.StatusNotFound, "site"))
+	p.Get("/", defaultHandler)
+
+	api := p.Group("/api")
+	api.Register404(respond(http.StatusNotFound, "api"))
+	api.Register405(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_, _ = io.WriteString(w, "api "+RequestVars(r).AllowedMethods()[0])
+	})
+	api.Get("/users/:id", defaultHandler)
+
+	v2 := api.Group("/v2")
+	v2.Register404(respond(http.StatusNotFound, "v2"))
+	v2.Get("/users/:id", defaultHandler)
+
+	accounts := api.Group("/accounts/:id")
+	accounts.Register404(respond(http.StatusNotFound, "account"))
+
+	docs := p.Group("/docs/")
+	docs.Register404(respond(http.StatusNotFound, "docs"))
+
+	// registering again replaces the handler
+	v2.Register404(respond(http.StatusNotFound, "v2 again"))
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/nope", http.StatusNotFound, "site"},
+		{http.MethodGet, "/apiary", http.StatusNotFound, "site"},
+		{http.MethodGet, "/api", http.StatusNotFound, "api"},
+		{http.MethodGet, "/api/nope", http.StatusNotFound, "api"},
+		{http.MethodGet, "/api/v2", http.StatusNotFound, "v2 again"},
+		{http.MethodGet, "/api/v2/nope", http.StatusNotFound, "v2 again"},
+		{http.MethodGet, "/api/v3/nope", http.StatusNotFound, "api"},
+		{http.MethodGet, "/api/accounts/1/nope", http.StatusNotFound, "account"},
+		{http.MethodGet, "/api/accounts", http.StatusNotFound, "api"},
+		{http.MethodGet, "/docs", http.StatusNotFound, "docs"},
+		{http.MethodGet, "/docs/nope", http.StatusNotFound, "docs"},
+		{http.MethodGet, "/api/users/1", http.StatusOK, "GET"},
+		// the group enables 405 handling beneath it's prefix, nested groups use the parent's
+		{http.MethodPost, "/api/users/1", http.StatusMethodNotAllowed, "api GET"},
+		{http.MethodPost, "/api/v2/users/1", http.StatusMethodNotAllowed, "api GET"},
+		{http.MethodPost, "/", http.StatusNotFound, "site"},
+	}
+
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		p.serveHTTP(w, r)
+		Equal(t, w.Code, tt.code)
+		Equal(t, w.Body.String(), tt.body)
+	}
+
+	r, _ := http.NewRequest(http.MethodPost, "/api/users/1", nil)
+	w := httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodGet})
+
+	// the Mux's 405 handling applies outside the group
+	p.RegisterMethodNotAllowed()
+	code, _ := request(http.MethodPost, "/", p)
+	Equal(t, code, http.StatusMethodNotAllowed)
+}
+
+func TestHostFallbacks(t *testing.T) {
+	p := New()
+	p.Register404(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "default")
+	})
+
+	api := p.Host("api.example.com")
+	api.Register404(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "api")
+	})
+
+	code, body := request(http.MethodGet, "http://api.example.com/nope", p)
+	Equal(t, code, http.StatusNotFound)
+	Equal(t, body, "api")
+
+	code, body = request(http.MethodGet, "http://www.example.com/nope", p)
+	Equal(t, code, http.StatusNotFound)
+	Equal(t, body, "default")
+}
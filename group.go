@@ -2,7 +2,7 @@ package pure
 
 import (
 	"net/http"
-	"strconv"
+	"net/url"
 	"strings"
 )
 
@@ -12,57 +12,148 @@ type IRouteGroup interface {
 	GroupWithNone(prefix string) IRouteGroup
 	GroupWithMore(prefix string, middleware ...Middleware) IRouteGroup
 	Group(prefix string) IRouteGroup
+	Register404(notFound http.HandlerFunc, middleware ...Middleware)
+	Register405(methodNotAllowed http.HandlerFunc, middleware ...Middleware)
 }
 
 // IRoutes interface for routes
 type IRoutes interface {
 	Use(...Middleware)
-	Any(string, http.HandlerFunc)
-	Get(string, http.HandlerFunc)
-	Post(string, http.HandlerFunc)
-	Delete(string, http.HandlerFunc)
-	Patch(string, http.HandlerFunc)
-	Put(string, http.HandlerFunc)
-	Options(string, http.HandlerFunc)
-	Head(string, http.HandlerFunc)
-	Connect(string, http.HandlerFunc)
-	Trace(string, http.HandlerFunc)
+	Any(string, http.HandlerFunc, ...RouteOption) *Route
+	Get(string, http.HandlerFunc, ...RouteOption) *Route
+	Post(string, http.HandlerFunc, ...RouteOption) *Route
+	Delete(string, http.HandlerFunc, ...RouteOption) *Route
+	Patch(string, http.HandlerFunc, ...RouteOption) *Route
+	Put(string, http.HandlerFunc, ...RouteOption) *Route
+	Options(string, http.HandlerFunc, ...RouteOption) *Route
+	Head(string, http.HandlerFunc, ...RouteOption) *Route
+	Connect(string, http.HandlerFunc, ...RouteOption) *Route
+	Trace(string, http.HandlerFunc, ...RouteOption) *Route
+	AnyE(string, HandlerFuncE, ...RouteOption) *Route
+	GetE(string, HandlerFuncE, ...RouteOption) *Route
+	PostE(string, HandlerFuncE, ...RouteOption) *Route
+	DeleteE(string, HandlerFuncE, ...RouteOption) *Route
+	PatchE(string, HandlerFuncE, ...RouteOption) *Route
+	PutE(string, HandlerFuncE, ...RouteOption) *Route
+	OptionsE(string, HandlerFuncE, ...RouteOption) *Route
+	HeadE(string, HandlerFuncE, ...RouteOption) *Route
+	ConnectE(string, HandlerFuncE, ...RouteOption) *Route
+	TraceE(string, HandlerFuncE, ...RouteOption) *Route
+	TryHandle(string, string, http.HandlerFunc, ...RouteOption) (*Route, error)
+	Mount(string, http.Handler)
+	MountStripPrefix(string, http.Handler)
 }
 
 // routeGroup struct containing all fields and methods for use.
 type routeGroup struct {
 	prefix     string
+	host       string // host pattern the group's routes match, blank for any host
 	middleware []Middleware
 	pure       *Mux
 }
 
 var _ IRouteGroup = &routeGroup{}
 
-func (g *routeGroup) handle(method string, path string, handler http.HandlerFunc) {
+// anyMethods are the HTTP methods registered by Any
+var anyMethods = []string{
+	http.MethodConnect,
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodTrace,
+}
+
+func (g *routeGroup) handle(method string, path string, handler http.HandlerFunc, opts ...RouteOption) *Route {
+	r, err := g.TryHandle(method, path, handler, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// TryHandle is the same as Handle except an error is returned when the route can not be
+// registered, rather than panicking, leaving the registered routes unchanged. Useful when
+// registering routes at runtime eg. from configuration or plugins; once serving, it must only
+// be called within Update, using it's provided group, as registering routes otherwise races
+// with the requests being served.
+//
+// The error is one of *DoubleSlashError, *DuplicateRouteError, *DuplicateParamError,
+// *WildcardConflictError, *CatchAllError, *InvalidPathError or *DuplicateNameError.
+func (g *routeGroup) TryHandle(method string, path string, handler http.HandlerFunc, opts ...RouteOption) (*Route, error) {
+	return g.tryHandle(method, path, handler, handlerName(handler), opts)
+}
+
+// tryHandle registers the handler, name being the function name of the handler as registered
+func (g *routeGroup) tryHandle(method string, path string, handler http.HandlerFunc, name string, opts []RouteOption) (*Route, error) {
 
 	if i := strings.Index(path, "//"); i != -1 {
-		panic("Bad path '" + path + "' contains duplicate // at index:" + strconv.Itoa(i))
+		return nil, &DoubleSlashError{Path: path, Index: i}
+	}
+
+	pattern := g.prefix + path
+	if pattern == blank {
+		pattern = basePath
+	}
+
+	o := routeOptions(opts)
+
+	if o.Name != blank {
+		if existing, ok := g.pure.table.names[o.Name]; ok && existing.pattern != pattern {
+			return nil, &DuplicateNameError{Name: o.Name, Path: existing.pattern}
+		}
 	}
 
-	h := handler
+	h := o.wrap(handler)
 
 	for i := len(g.middleware) - 1; i >= 0; i-- {
 		h = g.middleware[i](h)
 	}
 
-	tree := g.pure.trees[method]
+	t := g.pure.table
+	tree := t.routeTrees(g.host)[method]
 
 	if tree == nil {
 		tree = new(node)
-		g.pure.trees[method] = tree
 	}
 
-	pCount := tree.add(g.prefix+path, h)
+	tree, pCount, err := tree.add(pattern, h, g.pure)
+	if err != nil {
+		if e, ok := err.(*DuplicateRouteError); ok {
+			e.Method = method
+		}
+		return nil, err
+	}
+	t.setTree(g.host, method, tree)
+	t.allow(g.host, pattern, method)
 	pCount++
 
-	if pCount > g.pure.mostParams {
-		g.pure.mostParams = pCount
+	if pCount > t.mostParams {
+		t.mostParams = pCount
+	}
+
+	info := &RouteInfo{
+		Method:     method,
+		Host:       g.host,
+		Pattern:    pattern,
+		Handler:    name,
+		Middleware: len(g.middleware) + len(o.Middleware),
+		Metadata:   o.Metadata,
+	}
+	t.routes = append(t.routes, info)
+
+	r := &Route{
+		pattern: pattern,
+		infos:   []*RouteInfo{info},
+		pure:    g.pure,
 	}
+	if o.Name != blank {
+		r.Name(o.Name)
+	}
+	return r, nil
 }
 
 // Use adds a middleware handler to the group middleware chain.
@@ -71,81 +162,154 @@ func (g *routeGroup) Use(m ...Middleware) {
 }
 
 // Connect adds a CONNECT route & handler to the router.
-func (g *routeGroup) Connect(path string, h http.HandlerFunc) {
-	g.handle(http.MethodConnect, path, h)
+func (g *routeGroup) Connect(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodConnect, path, h, opts...)
 }
 
 // Delete adds a DELETE route & handler to the router.
-func (g *routeGroup) Delete(path string, h http.HandlerFunc) {
-	g.handle(http.MethodDelete, path, h)
+func (g *routeGroup) Delete(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodDelete, path, h, opts...)
 }
 
 // Get adds a GET route & handler to the router.
-func (g *routeGroup) Get(path string, h http.HandlerFunc) {
-	g.handle(http.MethodGet, path, h)
+func (g *routeGroup) Get(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodGet, path, h, opts...)
 }
 
 // Head adds a HEAD route & handler to the router.
-func (g *routeGroup) Head(path string, h http.HandlerFunc) {
-	g.handle(http.MethodHead, path, h)
+func (g *routeGroup) Head(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodHead, path, h, opts...)
 }
 
 // Options adds an OPTIONS route & handler to the router.
-func (g *routeGroup) Options(path string, h http.HandlerFunc) {
-	g.handle(http.MethodOptions, path, h)
+func (g *routeGroup) Options(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodOptions, path, h, opts...)
 }
 
 // Patch adds a PATCH route & handler to the router.
-func (g *routeGroup) Patch(path string, h http.HandlerFunc) {
-	g.handle(http.MethodPatch, path, h)
+func (g *routeGroup) Patch(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodPatch, path, h, opts...)
 }
 
 // Post adds a POST route & handler to the router.
-func (g *routeGroup) Post(path string, h http.HandlerFunc) {
-	g.handle(http.MethodPost, path, h)
+func (g *routeGroup) Post(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodPost, path, h, opts...)
 }
 
 // Put adds a PUT route & handler to the router.
-func (g *routeGroup) Put(path string, h http.HandlerFunc) {
-	g.handle(http.MethodPut, path, h)
+func (g *routeGroup) Put(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodPut, path, h, opts...)
 }
 
 // Trace adds a TRACE route & handler to the router.
-func (g *routeGroup) Trace(path string, h http.HandlerFunc) {
-	g.handle(http.MethodTrace, path, h)
+func (g *routeGroup) Trace(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(http.MethodTrace, path, h, opts...)
 }
 
 // Handle allows for any method to be registered with the given
 // route & handler. Allows for non standard methods to be used
 // like CalDavs PROPFIND and so forth.
-func (g *routeGroup) Handle(method string, path string, h http.HandlerFunc) {
-	g.handle(method, path, h)
+func (g *routeGroup) Handle(method string, path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.handle(method, path, h, opts...)
 }
 
 // Any adds a route & handler to the router for all HTTP methods.
-func (g *routeGroup) Any(path string, h http.HandlerFunc) {
-	g.Connect(path, h)
-	g.Delete(path, h)
-	g.Get(path, h)
-	g.Head(path, h)
-	g.Options(path, h)
-	g.Patch(path, h)
-	g.Post(path, h)
-	g.Put(path, h)
-	g.Trace(path, h)
+func (g *routeGroup) Any(path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	return g.Match(anyMethods, path, h, opts...)
 }
 
 // Match adds a route & handler to the router for multiple HTTP methods provided.
-func (g *routeGroup) Match(methods []string, path string, h http.HandlerFunc) {
+func (g *routeGroup) Match(methods []string, path string, h http.HandlerFunc, opts ...RouteOption) *Route {
+	r := &Route{
+		pattern: g.prefix + path,
+		pure:    g.pure,
+	}
 	for _, m := range methods {
-		g.handle(m, path, h)
+		mr := g.handle(m, path, h, opts...)
+		r.pattern = mr.pattern
+		r.infos = append(r.infos, mr.infos...)
+	}
+	return r
+}
+
+// Mount routes the prefix and every path beneath it, for all HTTP methods, to the
+// provided handler eg. an http.ServeMux or third-party application. The group's
+// middleware is applied and the request path is passed through unchanged.
+//
+// The methods registered by Any are routed like any other route, other methods
+// eg. PROPFIND are routed to the handler when not matching one of their routes.
+func (g *routeGroup) Mount(prefix string, h http.Handler) {
+	g.mount(prefix, h.ServeHTTP)
+}
+
+// MountStripPrefix is the same as Mount except the matched prefix is stripped
+// from the request's URL Path and RawPath before calling the handler, the
+// bare prefix being passed as "/".
+func (g *routeGroup) MountStripPrefix(prefix string, h http.Handler) {
+	g.mount(prefix, func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, stripPrefix(r))
+	})
+}
+
+func (g *routeGroup) mount(prefix string, h http.HandlerFunc) {
+	prefix = strings.TrimSuffix(prefix, basePath)
+
+	// a catch-all also matches the empty path so the bare prefix is only
+	// registered separately when it isn't the root
+	var patterns []string
+	if g.prefix+prefix != blank {
+		patterns = append(patterns, prefix)
+	}
+	patterns = append(patterns, prefix+"/*")
+
+	for _, pattern := range patterns {
+		g.Any(pattern, h)
+	}
+
+	// any other method is matched against the host's mounts
+	for i := len(g.middleware) - 1; i >= 0; i-- {
+		h = g.middleware[i](h)
+	}
+
+	t := g.pure.table
+	tree := t.mounts[g.host]
+	if tree == nil {
+		tree = new(node)
+	}
+	for _, pattern := range patterns {
+		var err error
+		if tree, _, err = tree.add(g.prefix+pattern, h, g.pure); err != nil {
+			panic(err)
+		}
+	}
+	t.mounts[g.host] = tree
+}
+
+// stripPrefix returns a shallow copy of the request with the prefix matched by
+// the mount stripped from its URL Path and RawPath
+func stripPrefix(r *http.Request) *http.Request {
+	rest := RequestVars(r).URLParam(WildcardParam)
+
+	// the unescaped index the remaining path starts at
+	i := len(r.URL.Path) - len(rest)
+
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = basePath + rest
+
+	if len(r.URL.RawPath) > 0 {
+		r2.URL.RawPath = basePath + r.URL.RawPath[rawIndex(r.URL.RawPath, i):]
 	}
+	return r2
 }
 
 // GroupWithNone creates a new sub router with specified prefix and no middleware attached.
 func (g *routeGroup) GroupWithNone(prefix string) IRouteGroup {
 	return &routeGroup{
 		prefix:     g.prefix + prefix,
+		host:       g.host,
 		pure:       g.pure,
 		middleware: make([]Middleware, 0),
 	}
@@ -155,6 +319,7 @@ func (g *routeGroup) GroupWithNone(prefix string) IRouteGroup {
 func (g *routeGroup) GroupWithMore(prefix string, middleware ...Middleware) IRouteGroup {
 	rg := &routeGroup{
 		prefix:     g.prefix + prefix,
+		host:       g.host,
 		pure:       g.pure,
 		middleware: make([]Middleware, len(g.middleware)),
 	}
@@ -167,6 +332,7 @@ func (g *routeGroup) GroupWithMore(prefix string, middleware ...Middleware) IRou
 func (g *routeGroup) Group(prefix string) IRouteGroup {
 	rg := &routeGroup{
 		prefix:     g.prefix + prefix,
+		host:       g.host,
 		pure:       g.pure,
 		middleware: make([]Middleware, len(g.middleware)),
 	}
@@ -247,3 +247,114 @@ func TestGrouplogic(t *testing.T) {
 	Equal(t, bb, 2)
 	Equal(t, cc, 1)
 }
+
+func TestMount(t *testing.T) {
+
+	mounted := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte(r.Method + "|" + r.URL.Path + "|" + r.URL.RawPath + "|" + r.Header.Get("X-Group"))); err != nil {
+			panic(err)
+		}
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", mounted)
+
+	p := New()
+	p.Use(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set("X-Group", "root")
+			next(w, r)
+		}
+	})
+	p.Get("/admin/login", defaultHandler)
+	p.Handle("PROPFIND", "/dav", defaultHandler)
+	p.Mount("/debug/", mounted)
+	p.MountStripPrefix("/admin", mounted)
+	p.Group("/tenants/:id").MountStripPrefix("/legacy", mux)
+
+	tests := []struct {
+		method string
+		url    string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/debug", http.StatusOK, "GET|/debug||root"},
+		{http.MethodPost, "/debug/pprof/profile", http.StatusOK, "POST|/debug/pprof/profile||root"},
+		{http.MethodGet, "/admin", http.StatusOK, "GET|/||root"},
+		{http.MethodGet, "/admin/", http.StatusOK, "GET|/||root"},
+		{http.MethodDelete, "/admin/users/1", http.StatusOK, "DELETE|/users/1||root"},
+		{http.MethodGet, "/admin/files/a%2Fb", http.StatusOK, "GET|/files/a/b|/files/a%2Fb|root"},
+		{http.MethodGet, "/admin/login", http.StatusOK, "GET"},
+		{http.MethodGet, "/tenants/a%20b/legacy/users", http.StatusOK, "GET|/users||root"},
+		{http.MethodGet, "/tenants/a%20b/legacy/other", http.StatusNotFound, ""},
+		{http.MethodGet, "/administrator", http.StatusNotFound, ""},
+		{"PROPFIND", "/debug/dav/x", http.StatusOK, "PROPFIND|/debug/dav/x||root"},
+		{"MKCOL", "/debug", http.StatusOK, "MKCOL|/debug||root"},
+		{"MKCOL", "/admin/dav/x", http.StatusOK, "MKCOL|/dav/x||root"},
+		{"PROPFIND", "/dav", http.StatusOK, "PROPFIND"},
+		{"PROPFIND", "/tenants/a%20b/legacy/users", http.StatusOK, "PROPFIND|/users||root"},
+		{"PROPFIND", "/other", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		code, body := request(tt.method, tt.url, p)
+		Equal(t, code, tt.code)
+		if tt.code == http.StatusOK {
+			Equal(t, body, tt.body)
+		}
+	}
+
+	p = New()
+	p.Mount("", mounted)
+
+	code, body := request(http.MethodGet, "/", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "GET|/||")
+
+	code, body = request(http.MethodPut, "/a/b", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "PUT|/a/b||")
+
+	code, body = request("PROPFIND", "/a/b", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "PROPFIND|/a/b||")
+}
+
+func TestMountNestedMux(t *testing.T) {
+
+	vars := func(w http.ResponseWriter, r *http.Request) {
+		rv := RequestVars(r)
+		if _, err := w.Write([]byte(rv.Pattern() + "|" + rv.URLParam(WildcardParam) + "|" + rv.URLParam("id"))); err != nil {
+			panic(err)
+		}
+	}
+
+	inner := New()
+	inner.Get("/api/users", vars)
+	inner.Get("/api/users/:id", vars)
+
+	outer := New()
+	outer.Mount("/api", inner.Serve())
+
+	tests := []struct {
+		url  string
+		body string
+	}{
+		{"/api/users", "/api/users||"},
+		{"/api/users/13", "/api/users/:id||13"},
+	}
+
+	for _, tt := range tests {
+		code, body := request(http.MethodGet, tt.url, outer)
+		Equal(t, code, http.StatusOK)
+		Equal(t, body, tt.body)
+	}
+
+	// the path rewritten by the outer mux is the inner static route's pattern
+	outer = New()
+	outer.MountStripPrefix("/v1", inner.Serve())
+
+	code, body := request(http.MethodGet, "/v1/api/users", outer)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "/api/users||")
+}
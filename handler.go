@@ -0,0 +1,157 @@
+package pure
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// HandlerFuncE is a handler returning an error, which is passed to the error
+// handler registered using Mux.RegisterErrorHandler to write the response.
+// It's registered using the E suffixed methods eg. GetE, and is converted to
+// an http.HandlerFunc so the existing Middleware composes as usual.
+type HandlerFuncE func(w http.ResponseWriter, r *http.Request) error
+
+// ErrorHandler writes the response for the error returned by a HandlerFuncE
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// HTTPError is an error with the HTTP status code, and message safe to expose
+// to the client, it's responded with by the default error handler
+//
+//	return &pure.HTTPError{Status: http.StatusBadRequest, Message: "invalid user", Err: err}
+type HTTPError struct {
+	// Status is the HTTP status code responded with
+	Status int
+
+	// Message is the public message responded with, when blank the status text is used
+	Message string
+
+	// Err is the internal cause, if any, which is never exposed to the client
+	Err error
+}
+
+func (e *HTTPError) Error() string {
+	s := strconv.Itoa(e.Status) + " " + e.message()
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// Unwrap returns the internal cause, if any
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// message returns the public message, or the status text when blank
+func (e *HTTPError) message() string {
+	if e.Message == blank {
+		return http.StatusText(e.Status)
+	}
+	return e.Message
+}
+
+// defaultErrorHandler responds with the status and public message of an *HTTPError,
+// wrapped or not, otherwise 500 Internal Server Error without exposing the error
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var e *HTTPError
+	if errors.As(err, &e) {
+		http.Error(w, e.message(), e.Status)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// RegisterErrorHandler allows for overriding of the error handler, which writes the response
+// for errors returned by HandlerFuncE's; by default *HTTPError's are responded with using their
+// status and public message, any other error with 500 Internal Server Error.
+func (p *Mux) RegisterErrorHandler(h ErrorHandler) {
+	p.httpError = h
+}
+
+// handlerE converts the HandlerFuncE to an http.HandlerFunc passing any error returned
+// to the error handler registered at the time of the request
+func (p *Mux) handlerE(h HandlerFuncE) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			p.httpError(w, r, err)
+		}
+	}
+}
+
+func (g *routeGroup) handleE(method string, path string, handler HandlerFuncE, opts []RouteOption) *Route {
+	r, err := g.tryHandle(method, path, g.pure.handlerE(handler), handlerName(handler), opts)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// ConnectE adds a CONNECT route & error returning handler to the router.
+func (g *routeGroup) ConnectE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodConnect, path, h, opts)
+}
+
+// DeleteE adds a DELETE route & error returning handler to the router.
+func (g *routeGroup) DeleteE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodDelete, path, h, opts)
+}
+
+// GetE adds a GET route & error returning handler to the router.
+func (g *routeGroup) GetE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodGet, path, h, opts)
+}
+
+// HeadE adds a HEAD route & error returning handler to the router.
+func (g *routeGroup) HeadE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodHead, path, h, opts)
+}
+
+// OptionsE adds an OPTIONS route & error returning handler to the router.
+func (g *routeGroup) OptionsE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodOptions, path, h, opts)
+}
+
+// PatchE adds a PATCH route & error returning handler to the router.
+func (g *routeGroup) PatchE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodPatch, path, h, opts)
+}
+
+// PostE adds a POST route & error returning handler to the router.
+func (g *routeGroup) PostE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodPost, path, h, opts)
+}
+
+// PutE adds a PUT route & error returning handler to the router.
+func (g *routeGroup) PutE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodPut, path, h, opts)
+}
+
+// TraceE adds a TRACE route & error returning handler to the router.
+func (g *routeGroup) TraceE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(http.MethodTrace, path, h, opts)
+}
+
+// HandleE is the same as Handle for an error returning handler.
+func (g *routeGroup) HandleE(method string, path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.handleE(method, path, h, opts)
+}
+
+// AnyE adds a route & error returning handler to the router for all HTTP methods.
+func (g *routeGroup) AnyE(path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	return g.MatchE(anyMethods, path, h, opts...)
+}
+
+// MatchE adds a route & error returning handler to the router for multiple HTTP methods provided.
+func (g *routeGroup) MatchE(methods []string, path string, h HandlerFuncE, opts ...RouteOption) *Route {
+	r := &Route{
+		pattern: g.prefix + path,
+		pure:    g.pure,
+	}
+	for _, m := range methods {
+		mr := g.handleE(m, path, h, opts)
+		r.pattern = mr.pattern
+		r.infos = append(r.infos, mr.infos...)
+	}
+	return r
+}
@@ -0,0 +1,105 @@
+package pure
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+var errDatabase = errors.New("connection refused")
+
+func userHandlerE(w http.ResponseWriter, r *http.Request) error {
+	switch RequestVars(r).URLParam("id") {
+	case "bad":
+		return &HTTPError{Status: http.StatusBadRequest, Message: "invalid user id", Err: errors.New("parsing 'bad'")}
+	case "gone":
+		return fmt.Errorf("loading user: %w", &HTTPError{Status: http.StatusGone})
+	case "db":
+		return errDatabase
+	}
+	_, _ = w.Write([]byte(RequestVars(r).URLParam("id")))
+	return nil
+}
+
+func TestHandlerFuncE(t *testing.T) {
+	var wrapped bool
+
+	p := New()
+	p.Use(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			wrapped = true
+			next(w, r)
+		}
+	})
+	p.GetE("/users/:id", userHandlerE, WithName("user"))
+	p.MatchE([]string{http.MethodPut, http.MethodPatch}, "/v2/users/:id", userHandlerE)
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/users/1", http.StatusOK, "1"},
+		{http.MethodGet, "/users/bad", http.StatusBadRequest, "invalid user id\n"},
+		{http.MethodGet, "/users/gone", http.StatusGone, "Gone\n"},
+		{http.MethodGet, "/users/db", http.StatusInternalServerError, "Internal Server Error\n"},
+		{http.MethodPatch, "/v2/users/bad", http.StatusBadRequest, "invalid user id\n"},
+	}
+
+	for _, tt := range tests {
+		wrapped = false
+		code, body := request(tt.method, tt.path, p)
+		Equal(t, code, tt.code)
+		Equal(t, body, tt.body)
+		Equal(t, wrapped, true)
+	}
+
+	routes := p.Routes()
+	Equal(t, len(routes), 3)
+	Equal(t, routes[0].Handler, "github.com/go-playground/pure/v5.userHandlerE")
+	Equal(t, routes[0].Name, "user")
+	Equal(t, routes[2].Method, http.MethodPatch)
+
+	// the error handler registered at the time of the request is used
+	var handled error
+	p.RegisterErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	code, _ := request(http.MethodGet, "/users/db", p)
+	Equal(t, code, http.StatusTeapot)
+	Equal(t, errors.Is(handled, errDatabase), true)
+}
+
+func TestHTTPError(t *testing.T) {
+	cause := errors.New("parsing 'bad'")
+
+	err := &HTTPError{Status: http.StatusBadRequest, Message: "invalid user id", Err: cause}
+	Equal(t, err.Error(), "400 invalid user id: parsing 'bad'")
+	Equal(t, errors.Is(err, cause), true)
+
+	err = &HTTPError{Status: http.StatusNotFound}
+	Equal(t, err.Error(), "404 Not Found")
+
+	w := httptest.NewRecorder()
+	defaultErrorHandler(w, nil, err)
+	Equal(t, w.Code, http.StatusNotFound)
+	Equal(t, w.Body.String(), "Not Found\n")
+}
@@ -0,0 +1,63 @@
+package pure
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// headResponseWriter discards the body written by a GET handler serving a HEAD request.
+// Writing the header is deferred until the handler returns so that the Content-Length of
+// the discarded body can be set, when not set by the handler.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int
+}
+
+// WriteHeader records the status code to write once the handler returns
+func (w *headResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+// Write discards the body, recording it's length
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.written += len(b)
+	return len(b), nil
+}
+
+// Flush is a no-op, there being no body to flush and the header being written once the
+// handler returns; implemented so that handlers streaming their response may be served
+func (w *headResponseWriter) Flush() {}
+
+// Hijack hijacks the underlying connection, if supported by the underlying http.ResponseWriter
+func (w *headResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the underlying http.ResponseWriter
+func (w *headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish writes the header, setting the Content-Length of the discarded body if not already
+func (w *headResponseWriter) finish() {
+	h := w.Header()
+	if w.written > 0 && h.Get(httpext.ContentLength) == blank && h.Get(httpext.TransferEncoding) == blank {
+		h.Set(httpext.ContentLength, strconv.Itoa(w.written))
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
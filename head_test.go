@@ -0,0 +1,128 @@
+package pure
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestAutomaticHEAD(t *testing.T) {
+
+	p := New()
+	p.RegisterMethodNotAllowed()
+	p.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if _, err := w.Write([]byte("user " + RequestVars(r).URLParam("id"))); err != nil {
+			panic(err)
+		}
+	})
+	p.Get("/created", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpext.ContentLength, "100")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("partial"))
+	})
+	p.Get("/explicit", defaultHandler)
+	p.Head("/explicit", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Explicit", "true")
+	})
+	p.Post("/posts", defaultHandler)
+	p.Get("/stream", func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 3; i++ {
+			_, _ = w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+		}
+	})
+
+	hf := p.Serve()
+
+	// disabled by default
+	r, _ := http.NewRequest(http.MethodHead, "/users/13", nil)
+	w := httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusMethodNotAllowed)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodGet})
+
+	p.SetAutomaticHEAD(true)
+
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Body.Len(), 0)
+	Equal(t, w.Header().Get("ETag"), `"v1"`)
+	Equal(t, w.Header().Get(httpext.ContentLength), "7")
+
+	r, _ = http.NewRequest(http.MethodHead, "/created", nil)
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusCreated)
+	Equal(t, w.Body.Len(), 0)
+	Equal(t, w.Header().Get(httpext.ContentLength), "100")
+
+	r, _ = http.NewRequest(http.MethodHead, "/explicit", nil)
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header().Get("X-Explicit"), "true")
+
+	// flushing is a no-op, the header being written once the handler returns
+	r, _ = http.NewRequest(http.MethodHead, "/stream", nil)
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Flushed, false)
+	Equal(t, w.Body.Len(), 0)
+	Equal(t, w.Header().Get(httpext.ContentLength), "15")
+
+	// fixed paths of GET routes are redirected
+	r, _ = http.NewRequest(http.MethodHead, "/users/13/", nil)
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusPermanentRedirect)
+	Equal(t, w.Header().Get("Location"), "/users/13")
+
+	r, _ = http.NewRequest(http.MethodHead, "/posts", nil)
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusMethodNotAllowed)
+
+	r, _ = http.NewRequest(http.MethodPost, "/users/13", nil)
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusMethodNotAllowed)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodGet, http.MethodHead})
+
+	r, _ = http.NewRequest(http.MethodPost, "/explicit", nil)
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusMethodNotAllowed)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodGet, http.MethodHead})
+
+	// through a real server
+	srv := httptest.NewServer(hf)
+	defer srv.Close()
+
+	res, err := http.Head(srv.URL + "/users/13")
+	Equal(t, err, nil)
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	Equal(t, err, nil)
+	Equal(t, res.StatusCode, http.StatusOK)
+	Equal(t, len(b), 0)
+	Equal(t, res.ContentLength, int64(7))
+	Equal(t, res.Header.Get("ETag"), `"v1"`)
+}
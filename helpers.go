@@ -10,12 +10,14 @@ import (
 	urlext "github.com/go-playground/pkg/v5/net/url"
 )
 
-// RequestVars returns the request scoped variables tracked by pure
+// RequestVars returns the request scoped variables tracked by pure.
+// NOTE: they're pooled and reused once the handler returns, so must not be retained
 func RequestVars(r *http.Request) ReqVars {
 
 	rv := r.Context().Value(defaultContextIdentifier)
 	if rv == nil {
-		return new(requestVars)
+		// static routes are served without storing the request vars
+		return &requestVars{pattern: r.URL.Path}
 	}
 
 	return rv.(*requestVars)
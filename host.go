@@ -0,0 +1,117 @@
+package pure
+
+import (
+	"net/http"
+	"strings"
+)
+
+// hostMatched is the handler stored on the host tree, the matched host's
+// route trees are looked up using the matched host pattern
+var hostMatched http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {}
+
+// Host creates a new route group, with it's own route trees, that only matches requests
+// for the given host and retains existing middleware. The host may contain params eg.
+// :tenant.example.com whose values are read like any other using RequestVars(r).URLParam("tenant").
+//
+// Hosts are matched case insensitively and without the port, any port of the given host
+// eg. localhost:8080 being ignored; requests for a host not matching any registered using
+// Host fall back to the routes registered directly on the Mux.
+func (p *Mux) Host(host string) IRouteGroup {
+	host = hostPattern(host)
+	if host == blank {
+		panic("host must not be blank")
+	}
+
+	t := p.table
+
+	if _, ok := t.hosts[host]; !ok {
+		tree := t.hostTree
+		if tree == nil {
+			tree = new(node)
+		}
+
+		tree, pCount, err := tree.add(host, hostMatched, p)
+		if err != nil {
+			panic(err)
+		}
+		t.hostTree = tree
+		pCount++
+
+		if pCount > t.mostParams {
+			t.mostParams = pCount
+		}
+		t.hosts[host] = make(map[string]*node)
+	}
+
+	rg := &routeGroup{
+		host:       host,
+		pure:       p,
+		middleware: make([]Middleware, len(p.middleware)),
+	}
+	copy(rg.middleware, p.middleware)
+	return rg
+}
+
+// SetTrustForwardedHost tells pure whether to use the X-Forwarded-Host
+// header, when present, over the request Host when matching the routes
+// registered using Host. Only enable when behind a trusted proxy. default false
+func (p *Mux) SetTrustForwardedHost(set bool) {
+	p.trustForwardedHost = set
+}
+
+// matchHost returns the host pattern matching the request, along with the
+// request vars containing any host params, otherwise blank for the default
+// route trees.
+func (p *Mux) matchHost(t *table, r *http.Request) (string, *requestVars) {
+	leaf, rv := t.hostTree.find(p.requestHost(r), nil, p)
+	if leaf == nil {
+		if rv != nil {
+			p.pool.Put(rv)
+		}
+		return blank, nil
+	}
+	return leaf.pattern, rv
+}
+
+// hostPattern returns the lowercase host pattern without the port, as the request
+// host is matched, so localhost:8080 is registered as localhost
+func hostPattern(host string) string {
+	if i := strings.LastIndexByte(host, ':'); i > 0 && isPort(host[i+1:]) {
+		host = host[:i]
+	}
+	return strings.ToLower(host)
+}
+
+// isPort returns if s is a non-empty port number
+func isPort(s string) bool {
+	if s == blank {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestHost returns the lowercase host, without the port, used for matching
+func (p *Mux) requestHost(r *http.Request) string {
+	host := r.Host
+
+	if p.trustForwardedHost {
+		if fh := r.Header.Get("X-Forwarded-Host"); fh != blank {
+			// may be a comma separated list when multiple proxies are involved, the first is the client requested host
+			if i := strings.IndexByte(fh, ','); i != -1 {
+				fh = fh[:i]
+			}
+			host = strings.TrimSpace(fh)
+		}
+	}
+
+	// strip the port, taking care not to strip part of an IPv6 address eg. [::1]:8080
+	if i := strings.LastIndexByte(host, ':'); i != -1 && strings.IndexByte(host[i:], ']') == -1 {
+		host = host[:i]
+	}
+	return strings.ToLower(host)
+}
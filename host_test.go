@@ -0,0 +1,176 @@
+package pure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestHostRouting(t *testing.T) {
+
+	hostHandler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			rv := RequestVars(r)
+			if _, err := w.Write([]byte(name + "|" + rv.URLParam("tenant") + "|" + rv.URLParam("id") + "|" + rv.Pattern())); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	p := New()
+	p.RegisterMethodNotAllowed()
+	p.Get("/users/:id", hostHandler("default"))
+
+	api := p.Host("API.example.com")
+	api.Get("/users/:id", hostHandler("api"))
+	api.Post("/users", hostHandler("api"))
+
+	tenant := p.Host(":tenant.example.com")
+	tenant.Get("/", hostHandler("tenant"))
+	tenant.Group("/users").Get("/:id", hostHandler("tenant"))
+
+	p.Host("static.example.com").Get("/users/:id<int>", hostHandler("static"))
+
+	// the port is ignored, as when matching the request host
+	p.Host("localhost:8080").Get("/users/:id", hostHandler("local"))
+
+	tests := []struct {
+		method string
+		url    string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "http://api.example.com/users/1", http.StatusOK, "api||1|/users/:id"},
+		{http.MethodGet, "http://Api.Example.com:8080/users/1", http.StatusOK, "api||1|/users/:id"},
+		{http.MethodPost, "http://api.example.com/users", http.StatusOK, "api|||/users"},
+		{http.MethodPost, "http://api.example.com/users/1", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "http://acme.example.com/", http.StatusOK, "tenant|acme||/"},
+		{http.MethodGet, "http://acme.example.com/users/2", http.StatusOK, "tenant|acme|2|/users/:id"},
+		{http.MethodGet, "http://eu.acme.example.com/users/2", http.StatusOK, "tenant|eu.acme|2|/users/:id"},
+		{http.MethodGet, "http://acme.example.com/posts", http.StatusNotFound, ""},
+		{http.MethodGet, "http://static.example.com/users/3", http.StatusOK, "static||3|/users/:id<int>"},
+		{http.MethodGet, "http://static.example.com/users/joeybloggs", http.StatusNotFound, ""},
+		{http.MethodGet, "http://example.com/users/4", http.StatusOK, "default||4|/users/:id"},
+		{http.MethodGet, "http://[::1]:8080/users/5", http.StatusOK, "default||5|/users/:id"},
+		{http.MethodGet, "/users/6", http.StatusOK, "default||6|/users/:id"},
+		{http.MethodGet, "http://localhost:8080/users/7", http.StatusOK, "local||7|/users/:id"},
+		{http.MethodGet, "http://localhost:9090/users/8", http.StatusOK, "local||8|/users/:id"},
+	}
+
+	for _, tt := range tests {
+		code, body := request(tt.method, tt.url, p)
+		Equal(t, code, tt.code)
+		if tt.code == http.StatusOK {
+			Equal(t, body, tt.body)
+		}
+	}
+
+	routes := p.Routes()
+	Equal(t, routes[0].Host, "")
+	Equal(t, routes[1].Host, "api.example.com")
+	Equal(t, routes[3].Host, ":tenant.example.com")
+	Equal(t, routes[len(routes)-1].Host, "localhost")
+
+	PanicMatches(t, func() { p.Host("") }, "host must not be blank")
+	PanicMatches(t, func() { api.Get("/users/:id", hostHandler("api")) }, "handlers are already registered for path '/users/:id'")
+}
+
+func TestForwardedHost(t *testing.T) {
+
+	p := New()
+	p.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte("default")); err != nil {
+			panic(err)
+		}
+	})
+	p.Host(":tenant.example.com").Get("/", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte(RequestVars(r).URLParam("tenant"))); err != nil {
+			panic(err)
+		}
+	})
+
+	hf := p.Serve()
+
+	r, _ := http.NewRequest(http.MethodGet, "http://internal:8080/", nil)
+	r.Header.Set("X-Forwarded-Host", "acme.example.com, proxy.example.com")
+	w := httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Body.String(), "default")
+
+	p.SetTrustForwardedHost(true)
+
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Body.String(), "acme")
+
+	r.Header.Del("X-Forwarded-Host")
+	r.Host = "globex.example.com:443"
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Body.String(), "globex")
+}
+
+func TestRemoveHost(t *testing.T) {
+
+	p := New()
+	p.Get("/users/:id", idHandler)
+	p.Host("api.example.com").Get("/users/:id", defaultHandler)
+	p.Host(":tenant.example.com").Get("/users/:id", defaultHandler)
+
+	Equal(t, p.RemoveHost("api.example.com", http.MethodGet, "/unknown"), false)
+	Equal(t, p.RemoveHost("unknown.example.com", http.MethodGet, "/users/:id"), false)
+	Equal(t, p.DisableHost("unknown.example.com", http.MethodGet, "/users/:id", http.StatusGone), false)
+	Equal(t, p.EnableHost("unknown.example.com", http.MethodGet, "/users/:id"), false)
+
+	Equal(t, p.DisableHost(":Tenant.example.com", http.MethodGet, "/users/:id", http.StatusGone), true)
+
+	code, _ := request(http.MethodGet, "http://acme.example.com/users/1", p)
+	Equal(t, code, http.StatusGone)
+	Equal(t, p.Routes()[2].Disabled, http.StatusGone)
+
+	// the routes of other hosts, and those registered directly on the Mux, are unaffected
+	code, body := request(http.MethodGet, "http://example.com/users/1", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "1")
+
+	Equal(t, p.EnableHost(":tenant.example.com", http.MethodGet, "/users/:id"), true)
+
+	code, body = request(http.MethodGet, "http://acme.example.com/users/1", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, http.MethodGet)
+	Equal(t, p.Routes()[2].Disabled, 0)
+
+	Equal(t, p.RemoveHost("api.example.com:443", http.MethodGet, "/users/:id"), true)
+	Equal(t, p.RemoveHost("api.example.com", http.MethodGet, "/users/:id"), false)
+
+	code, _ = request(http.MethodGet, "http://api.example.com/users/1", p)
+	Equal(t, code, http.StatusNotFound)
+
+	routes := p.Routes()
+	Equal(t, len(routes), 2)
+	Equal(t, routes[0].Host, "")
+	Equal(t, routes[1].Host, ":tenant.example.com")
+
+	// removing the route registered directly on the Mux leaves the host's
+	Equal(t, p.Remove(http.MethodGet, "/users/:id"), true)
+
+	code, body = request(http.MethodGet, "http://acme.example.com/users/1", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, http.MethodGet)
+}
@@ -8,7 +8,7 @@ package pure
 
 import (
 	"net/http"
-	"net/url"
+	"strings"
 )
 
 type nodeType uint8
@@ -21,21 +21,47 @@ const (
 
 type existingParams map[string]struct{}
 
+// node is a node of the radix tree. Static children are indexed by their first
+// byte, a node may also have a single param child and a single catch-all child.
+// When matching, static children take precedence over the param child which
+// takes precedence over the catch-all child.
 type node struct {
-	path      string
-	indices   string
-	children  []*node
-	handler   http.HandlerFunc
-	priority  uint32
-	nType     nodeType
-	wildChild bool
+	path     string // static path segment, or the param/catch-all definition eg. :id<int>
+	indices  string
+	children []*node // static children
+	param    *node   // param child
+	catchAll *node   // catch-all child
+	handler  http.HandlerFunc
+	disabled http.HandlerFunc // responds in place of the handler while the route is disabled
+	pattern  string           // full registered pattern, only set on nodes with a handler
+	key      string           // param name, only set on param and catch-all nodes
+	priority uint32
+	// constraint the param value must satisfy, only set on param nodes
+	constraint Constraint
+	nType      nodeType
 }
 
-func (e existingParams) check(param string, path string) {
-	if _, ok := e[param]; ok {
-		panic("Duplicate param name '" + param + "' detected for route '" + path + "'")
+func (e existingParams) check(param string, path string) error {
+	if i := strings.IndexByte(param, constraintStartByte); i != -1 {
+		param = param[:i]
 	}
-	e[param] = struct{}{}
+	// params and catch-alls share the same names eg. :path and *path
+	if _, ok := e[param[1:]]; ok {
+		return &DuplicateParamError{Path: path, Param: param}
+	}
+	e[param[1:]] = struct{}{}
+	return nil
+}
+
+// copy returns a shallow copy of the node, with it's own children slice, so
+// that it can be modified without affecting the tree it belongs to
+func (n *node) copy() *node {
+	c := *n
+	if n.children != nil {
+		c.children = make([]*node, len(n.children))
+		copy(c.children, n.children)
+	}
+	return &c
 }
 
 // increments priority of the given child and reorders if necessary
@@ -63,324 +89,573 @@ func (n *node) incrementChildPrio(pos int) int {
 
 // addRoute adds a node with the given handle to the path.
 // here we set a Middleware because we have  to transfer all route's middlewares (it's a chain of functions) (with it's handler) to the node
-func (n *node) add(path string, handler http.HandlerFunc) (lp uint8) {
-	var err error
+//
+// The tree is never modified, the nodes along the path are copied as the route is
+// inserted and the new root returned, so it's safe to add to a tree being served
+// and the tree is left unchanged when an error is returned.
+func (n *node) add(path string, handler http.HandlerFunc, mux *Mux) (root *node, lp uint8, err error) {
 	if path == blank {
 		path = basePath
 	}
 
 	existing := make(existingParams)
+	pattern := path
 	fullPath := path
 
-	if path, err = url.QueryUnescape(path); err != nil {
-		panic("Query Unescape Error on path '" + fullPath + "': " + err.Error())
+	if path, err = mux.routePath(path); err != nil {
+		return nil, 0, &InvalidPathError{Path: fullPath, Reason: "Query Unescape Error", Err: err, format: onPath}
 	}
 
 	fullPath = path
 
-	n.priority++
-	numParams := countParams(path)
-	lp = numParams
-
-	// non-empty tree
-	if len(n.path) > 0 || len(n.children) > 0 {
-	walk:
-		for {
-			// Find the longest common prefix.
-			// This also implies that the common prefix contains no : or *
-			// since the existing key can't contain those chars.
-			i := 0
-			max := min(len(path), len(n.path))
-			for i < max && path[i] == n.path[i] {
-				i++
-			}
+	count := countParams(path)
+	if count >= 255 {
+		return nil, 0, &InvalidPathError{Path: fullPath, Reason: "too many parameters defined in path, max is 255", format: noPath}
+	}
+	lp = uint8(count)
+
+	root = n.copy()
+	root.nType = isRoot
+	root.priority++
+	cn := root
+
+	for len(path) > 0 {
+		switch path[0] {
+		case paramByte:
+			cn, path, err = cn.insertParam(existing, path, fullPath, mux)
+		case wildByte:
+			cn, path, err = cn.insertCatchAll(existing, path, fullPath)
+		default:
+			cn, path = cn.insertStatic(path)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
 
-			// Split edge
-			if i < len(n.path) {
-				child := node{
-					path:      n.path[i:],
-					wildChild: n.wildChild,
-					indices:   n.indices,
-					children:  n.children,
-					handler:   n.handler,
-					priority:  n.priority - 1,
-				}
+	if cn.handler != nil {
+		return nil, 0, &DuplicateRouteError{Path: fullPath}
+	}
+	cn.handler = handler
+	cn.pattern = pattern
+	return
+}
 
-				n.children = []*node{&child}
-				// []byte for proper unicode char conversion, see httprouter #65
-				n.indices = string([]byte{n.path[i]})
-				n.path = path[:i]
-				n.handler = nil
-				n.wildChild = false
-			}
+// insertStatic inserts the static portion of the path, up until the next wildcard,
+// splitting the existing static child as required. It returns the node the remaining
+// path is to be inserted into along with the remaining path.
+func (n *node) insertStatic(path string) (*node, string) {
+	end := 0
+	for end < len(path) && path[end] != paramByte && path[end] != wildByte {
+		end++
+	}
 
-			// Make new node a child of this node
-			if i < len(path) {
-				path = path[i:]
+	c := path[0]
 
-				if n.wildChild {
-					n = n.children[0]
-					n.priority++
-					numParams--
+	// Check if a child with the next path byte exists
+	for i := 0; i < len(n.indices); i++ {
+		if c != n.indices[i] {
+			continue
+		}
+		n.children[i] = n.children[i].copy()
+		i = n.incrementChildPrio(i)
+		child := n.children[i]
+
+		// Find the longest common prefix.
+		j := 0
+		max := min(end, len(child.path))
+		for j < max && path[j] == child.path[j] {
+			j++
+		}
 
-					existing.check(n.path, fullPath)
+		// Split edge
+		if j < len(child.path) {
+			split := &node{
+				path: child.path[:j],
+				// []byte for proper unicode char conversion, see httprouter #65
+				indices:  string([]byte{child.path[j]}),
+				children: []*node{child},
+				priority: child.priority,
+			}
+			child.path = child.path[j:]
+			child.priority--
+			n.children[i] = split
+			child = split
+		}
+		return child, path[j:]
+	}
 
-					// Check if the wildcard matches
-					if len(path) >= len(n.path) && n.path == path[:len(n.path)] {
+	// Otherwise insert it
+	// []byte for proper unicode char conversion, see httprouter #65
+	n.indices += string([]byte{c})
+	child := &node{
+		path: path[:end],
+	}
+	n.children = append(n.children, child)
+	n.incrementChildPrio(len(n.indices) - 1)
+	return child, path[end:]
+}
 
-						// check for longer wildcard, e.g. :name and :names
-						if len(n.path) >= len(path) || path[len(n.path)] == slashByte {
-							continue walk
-						}
-					}
+// insertParam inserts the param at the start of the path, returning the param node
+// and the remaining path.
+func (n *node) insertParam(existing existingParams, path string, fullPath string, mux *Mux) (*node, string, error) {
 
-					panic("path segment '" + path +
-						"' conflicts with existing wildcard '" + n.path +
-						"' in path '" + fullPath + "'")
-				}
+	// find wildcard end, the name followed by an optional constraint, anything
+	// after it up to the next wildcard is a static literal eg. /:name.:ext
+	end := wildcardEnd(fullPath, len(fullPath)-len(path)) - (len(fullPath) - len(path))
 
-				c := path[0]
+	if end < len(path) {
+		switch path[end] {
+		case constraintStartByte:
+			return nil, blank, &InvalidPathError{Path: fullPath, Reason: "unterminated constraint"}
+		case paramByte, wildByte:
+			return nil, blank, &InvalidPathError{Path: fullPath, Reason: "wildcards must be separated by a static literal, has: '" + path + "'"}
+		}
+	}
 
-				// slash after param
-				if n.nType == hasParams && c == slashByte && len(n.children) == 1 {
-					n = n.children[0]
-					n.priority++
-					continue walk
-				}
+	key, constraint := splitParam(path[1:end])
 
-				// Check if a child with the next path byte exists
-				for i := 0; i < len(n.indices); i++ {
-					if c == n.indices[i] {
-						i = n.incrementChildPrio(i)
-						n = n.children[i]
-						continue walk
-					}
-				}
+	// check if the wildcard has a name
+	if len(key) == 0 {
+		return nil, blank, &InvalidPathError{Path: fullPath, Reason: "wildcards must be named with a non-empty name"}
+	}
 
-				// Otherwise insert it
-				if c != paramByte && c != wildByte {
+	if n.catchAll != nil {
+		return nil, blank, &WildcardConflictError{Path: fullPath, Segment: "/" + path, Existing: "/" + n.catchAll.path}
+	}
 
-					// []byte for proper unicode char conversion, see httprouter #65
-					n.indices += string([]byte{c})
-					child := &node{}
-					n.children = append(n.children, child)
-					n.incrementChildPrio(len(n.indices) - 1)
-					n = child
-				}
-				n.insertChild(numParams, existing, path, fullPath, handler)
-				return
+	if err := existing.check(path[:end], fullPath); err != nil {
+		return nil, blank, err
+	}
 
-			} else if i == len(path) { // Make node a (in-path) leaf
-				if n.handler != nil {
-					panic("handlers are already registered for path '" + fullPath + "'")
-				}
-				n.handler = handler
-			}
-			return
+	if n.param != nil {
+		if n.param.path != path[:end] {
+			return nil, blank, &WildcardConflictError{Path: fullPath, Segment: path, Existing: n.param.path}
 		}
-	} else { // Empty tree
-		n.insertChild(numParams, existing, path, fullPath, handler)
-		n.nType = isRoot
+		n.param = n.param.copy()
+		n.param.priority++
+		return n.param, path[end:], nil
 	}
-	return
+
+	child := &node{
+		path:     path[:end],
+		key:      key,
+		nType:    hasParams,
+		priority: 1,
+	}
+	if len(constraint) > 0 {
+		var err error
+		if child.constraint, err = mux.constraint(constraint, fullPath); err != nil {
+			return nil, blank, err
+		}
+	}
+	n.param = child
+	return child, path[end:], nil
 }
 
-func (n *node) insertChild(numParams uint8, existing existingParams, path string, fullPath string, handler http.HandlerFunc) {
-	var offset int // already handled bytes of the path
+// insertCatchAll inserts the catch-all at the start of the path, returning the
+// catch-all node and the remaining path. The catch-all may be followed by a
+// static suffix beginning with '/' eg. /repos/:owner/*path/blob
+func (n *node) insertCatchAll(existing existingParams, path string, fullPath string) (*node, string, error) {
+	end := wildcardEnd(fullPath, len(fullPath)-len(path)) - (len(fullPath) - len(path))
+	if end != len(path) && path[end] != slashByte {
+		return nil, blank, &CatchAllError{Path: fullPath, Reason: "Character after the * symbol is not permitted", format: commaPath}
+	}
 
-	// find prefix until first wildcard (beginning with paramByte' or wildByte')
-	for i, max := 0, len(path); numParams > 0; i++ {
+	if len(n.path) == 0 || n.path[len(n.path)-1] != slashByte {
+		return nil, blank, &CatchAllError{Path: fullPath, Reason: "no / before catch-all"}
+	}
 
-		c := path[i]
-		if c != paramByte && c != wildByte {
-			continue
-		}
+	if n.param != nil {
+		return nil, blank, &WildcardConflictError{Path: fullPath, Segment: path, Existing: n.param.path}
+	}
 
-		// find wildcard end (either '/' or path end)
-		end := i + 1
-		for end < max && path[end] != slashByte {
-			switch path[end] {
-			// the wildcard name must not contain ':' and '*'
-			case paramByte, wildByte:
-				panic("only one wildcard per path segment is allowed, has: '" +
-					path[i:] + "' in path '" + fullPath + "'")
-			default:
-				end++
-			}
-		}
+	if err := existing.check(path[:end], fullPath); err != nil {
+		return nil, blank, err
+	}
 
-		// check if this Node existing children which would be
-		// unreachable if we insert the wildcard here
-		if len(n.children) > 0 {
-			panic("wildcard route '" + path[i:end] +
-				"' conflicts with existing children in path '" + fullPath + "'")
+	if n.catchAll != nil {
+		if n.catchAll.path != path[:end] {
+			return nil, blank, &WildcardConflictError{Path: fullPath, Segment: path, Existing: n.catchAll.path}
 		}
+		n.catchAll = n.catchAll.copy()
+		n.catchAll.priority++
+		return n.catchAll, path[end:], nil
+	}
 
-		if c == paramByte { // param
-			// check if the wildcard has a name
-			if end-i < 2 {
-				panic("wildcards must be named with a non-empty name in path '" + fullPath + "'")
-			}
+	if n.handler != nil && end == len(path) {
+		return nil, blank, &CatchAllError{Path: fullPath, Reason: "catch-all conflicts with existing handle for the path segment root"}
+	}
 
-			// split path at the beginning of the wildcard
-			if i > 0 {
-				n.path = path[offset:i]
-				offset = i
-			}
+	n.catchAll = &node{
+		path:     path[:end],
+		key:      catchAllKey(path[1:end]),
+		nType:    matchesAny,
+		priority: 1,
+	}
+	return n.catchAll, path[end:], nil
+}
 
-			child := &node{
-				nType: hasParams,
-			}
-			n.children = []*node{child}
-			n.wildChild = true
-			n = child
-			n.priority++
-			numParams--
+// modify returns a copy of the tree with the node having the handler registered for the
+// path, as registered not matched, replaced by the result of fn which is passed a copy
+// of the node. The tree itself is never modified so it's safe to modify a tree being served.
+//
+// Nodes left without a handler, eg. when removing the route, or children are pruned and
+// static nodes left with only a single static child merged with it. false is returned
+// if no handler is registered for the path.
+func (n *node) modify(path string, fn func(leaf *node)) (*node, bool) {
+	return n.modifyPath(path, path, fn)
+}
 
-			// if the path doesn't end with the wildcard, then there
-			// will be another non-wildcard subpath starting with '/'
-			if end < max {
+// modifyPath modifies the node having the handler registered for the path, the remainder
+// of the full path, as registered, after the nodes walked so far.
+func (n *node) modifyPath(path string, fullPath string, fn func(leaf *node)) (*node, bool) {
+	c := n.copy()
 
-				existing.check(path[offset:end], fullPath)
+	if len(path) == 0 {
+		if n.handler == nil {
+			return nil, false
+		}
+		fn(c)
+		c.priority = c.childPriority()
+		return c.compact(), true
+	}
 
-				n.path = path[offset:end]
-				offset = end
+	var child *node
+	var ok bool
 
-				child := &node{
-					priority: 1,
-				}
-				n.children = []*node{child}
-				n = child
-			}
+	switch path[0] {
+	case paramByte, wildByte:
+		end := wildcardEnd(fullPath, len(fullPath)-len(path)) - (len(fullPath) - len(path))
 
-		} else { // catchAll
-			if end != max || numParams > 1 {
-				panic("Character after the * symbol is not permitted, path '" + fullPath + "'")
-			}
+		wild := &c.param
+		if path[0] == wildByte {
+			wild = &c.catchAll
+		}
+		if *wild == nil || (*wild).path != path[:end] {
+			return nil, false
+		}
+		if child, ok = (*wild).modifyPath(path[end:], fullPath, fn); !ok {
+			return nil, false
+		}
+		*wild = child
 
-			if len(n.path) > 0 && n.path[len(n.path)-1] == '/' {
-				panic("catch-all conflicts with existing handle for the path segment root in path '" + fullPath + "'")
-			}
+	default:
+		i := strings.IndexByte(n.indices, path[0])
+		if i == -1 || !strings.HasPrefix(path, n.children[i].path) {
+			return nil, false
+		}
+		if child, ok = n.children[i].modifyPath(path[len(n.children[i].path):], fullPath, fn); !ok {
+			return nil, false
+		}
+		if child == nil {
+			c.children = append(c.children[:i], c.children[i+1:]...)
+			c.indices = c.indices[:i] + c.indices[i+1:]
+		} else {
+			c.children[i] = child
+		}
+	}
 
-			// currently fixed width 1 for '/'
-			i--
-			if path[i] != slashByte {
-				panic("no / before catch-all in path '" + fullPath + "'")
-			}
+	c.priority = c.childPriority()
+	return c.compact(), true
+}
 
-			n.path = path[offset:i]
+// childPriority returns the number of handlers registered at and below the node,
+// which is the priority of the node
+func (n *node) childPriority() (prio uint32) {
+	if n.handler != nil {
+		prio++
+	}
+	for _, child := range n.children {
+		prio += child.priority
+	}
+	if n.param != nil {
+		prio += n.param.priority
+	}
+	if n.catchAll != nil {
+		prio += n.catchAll.priority
+	}
+	return
+}
 
-			// first node: catchAll node with empty path
-			child := &node{
-				wildChild: true,
-				nType:     matchesAny,
-			}
-			n.children = []*node{child}
-			n.indices = string(path[i])
-			n = child
-			n.priority++
-
-			// second node: node holding the variable
-			child = &node{
-				path:     path[i:],
-				nType:    matchesAny,
-				handler:  handler,
-				priority: 1,
+// compact returns nil for a node that is no longer needed, having no handler or children,
+// or when a static node has only a single static child the child merged with the node.
+// The root node is always kept as is.
+func (n *node) compact() *node {
+	if n.nType == isRoot || n.handler != nil || n.param != nil || n.catchAll != nil {
+		return n
+	}
+	switch len(n.children) {
+	case 0:
+		return nil
+	case 1:
+		if n.nType == 0 {
+			merged := *n.children[0]
+			merged.path = n.path + merged.path
+			return &merged
+		}
+	}
+	return n
+}
+
+// Returns the node of the route registered with the given path (key), nil if none.
+// Param values are appended to the provided request vars, acquiring them if not already,
+// eg. those of the matched host. Static routes are matched without acquiring them.
+func (n *node) find(path string, rv *requestVars, mux *Mux) (*node, *requestVars) {
+	l := paramsLen(rv)
+
+	// fast path, iteratively walk the nodes having only a single child which may match the
+	// path; those with only static children or only a param child, without a constraint or
+	// static literal following it within the segment, which never require backtracking
+walk:
+	for len(path) > 0 && n.catchAll == nil {
+		p := n.param
+		if p == nil {
+			c := path[0]
+			for i := 0; i < len(n.indices); i++ {
+				if c == n.indices[i] {
+					if child := n.children[i]; len(path) >= len(child.path) && path[:len(child.path)] == child.path {
+						path = path[len(child.path):]
+						n = child
+						continue walk
+					}
+					break
+				}
 			}
-			n.children = []*node{child}
-			return
+			resetParams(rv, l)
+			return nil, rv
 		}
+
+		if len(n.children) > 0 || p.constraint != nil || (len(p.indices) > 0 && p.indices != "/") {
+			break
+		}
+
+		// find param end (either '/' or path end)
+		end := 0
+		for end < len(path) && path[end] != slashByte {
+			end++
+		}
+		if end == 0 {
+			resetParams(rv, l)
+			return nil, rv
+		}
+		if rv == nil {
+			rv = mux.getRequestVars()
+		}
+		value := path[:end]
+		if mux.useRawPath {
+			value = unescapeParam(value)
+		}
+		rv.params = append(rv.params, urlParam{key: p.key, value: value})
+		path = path[end:]
+		n = p
 	}
-	if n.nType == hasParams {
-		existing.check(path[offset:], fullPath)
+
+	if len(path) == 0 && n.handler != nil {
+		return n, rv
 	}
+	return n.lookup(path, rv, mux, false, l)
+}
 
-	// insert remaining path part and handle to the leaf
-	n.path = path[offset:]
-	n.handler = handler
+// findFold is the same as find except static segments are matched case insensitively.
+func (n *node) findFold(path string, rv *requestVars, mux *Mux) (*node, *requestVars) {
+	return n.lookup(path, rv, mux, true, paramsLen(rv))
 }
 
-// Returns the handle registered with the given path (key).
-func (n *node) find(path string, mux *Mux) (handler http.HandlerFunc, rv *requestVars) {
+// lookup matches the path, the params saved on the request vars beyond l being removed if not found
+func (n *node) lookup(path string, rv *requestVars, mux *Mux, fold bool, l int) (*node, *requestVars) {
+	if leaf := n.match(path, &rv, mux, fold); leaf != nil {
+		return leaf, rv
+	}
+	resetParams(rv, l)
+	return nil, rv
+}
+
+// match walks the tree returning the node with a handler matching the path, if any,
+// saving any param values on the request vars, acquiring them if not already.
+//
+// Static children are tried first, then the param child and lastly the catch-all child.
+// Only nodes having more than one of these require backtracking, which is done recursively,
+// otherwise the tree is walked iteratively, as by find's fast path. When fold is set static
+// children are matched case insensitively, which may match more than one, so are always
+// tried recursively.
+func (n *node) match(path string, rv **requestVars, mux *Mux, fold bool) *node {
 
 walk: // Outer loop for walking the tree
 	for {
-		if len(path) > len(n.path) {
-			if path[:len(n.path)] == n.path {
-				path = path[len(n.path):]
+		if len(path) == 0 {
+			// We should have reached the node containing the handle.
+			// Check if this node has a handle registered.
+			if n.handler != nil {
+				return n
+			}
+			// a catch-all also matches an empty remaining path
+			if n.catchAll != nil && n.catchAll.handler != nil {
+				addParam(rv, mux, n.catchAll, path)
+				return n.catchAll
+			}
+			return nil
+		}
 
-				// If this node does not have a wildcard (param or catchAll)
-				// child,  we can just look up the next child node and continue
-				// to walk down the tree
-				if !n.wildChild {
-					c := path[0]
-					for i := 0; i < len(n.indices); i++ {
-						if c == n.indices[i] {
-							n = n.children[i]
-							continue walk
-						}
+		if fold {
+			for _, child := range n.children {
+				if len(path) >= len(child.path) && strings.EqualFold(path[:len(child.path)], child.path) {
+					l := paramsLen(*rv)
+					if leaf := child.match(path[len(child.path):], rv, mux, fold); leaf != nil {
+						return leaf
 					}
-					return
+					resetParams(*rv, l)
 				}
-
-				// handle wildcard child
-				n = n.children[0]
-				switch n.nType {
-				case hasParams:
-					// find param end (either '/' or path end)
-					end := 0
-					for end < len(path) && path[end] != slashByte {
-						end++
-					}
-					if rv == nil {
-						rv = mux.pool.Get().(*requestVars)
-						rv.params = rv.params[0:0]
+			}
+		} else {
+			// find the static child for the next path byte, if any
+			var next *node
+			c := path[0]
+			for i := 0; i < len(n.indices); i++ {
+				if c == n.indices[i] {
+					if child := n.children[i]; len(path) >= len(child.path) && path[:len(child.path)] == child.path {
+						next = child
 					}
+					break
+				}
+			}
+
+			if next != nil {
+				// If this node does not have a wildcard (param or catchAll)
+				// child, we can just continue to walk down the tree
+				if n.param == nil && n.catchAll == nil {
+					path = path[len(next.path):]
+					n = next
+					continue walk
+				}
 
-					// save param value
-					i := len(rv.params)
-					rv.params = rv.params[:i+1] // expand slice within preallocated capacity
-					rv.params[i].key = n.path[1:]
-					rv.params[i].value = path[:end]
-
-					// we need to go deeper!
-					if end < len(path) {
-						if len(n.children) > 0 {
-							path = path[end:]
-							n = n.children[0]
-							continue walk
-						}
-						return
+				l := paramsLen(*rv)
+				if leaf := next.match(path[len(next.path):], rv, mux, fold); leaf != nil {
+					return leaf
+				}
+				resetParams(*rv, l)
+			}
+		}
+
+		if p := n.param; p != nil {
+			// find param end (either '/' or path end)
+			end := 0
+			for end < len(path) && path[end] != slashByte {
+				end++
+			}
+
+			// the param is followed by a static literal within the segment eg. /:name.:ext
+			// so it may also end where any of the static children begin, shortest first
+			if len(p.indices) > 1 || (len(p.indices) == 1 && p.indices[0] != slashByte) {
+				for i := 1; i < end; i++ {
+					if !hasIndex(p.indices, path[i], fold) || !p.accepts(path[:i], mux) {
+						continue
 					}
-					if n.handler != nil {
-						handler = n.handler
-						return
+					l := paramsLen(*rv)
+					addParam(rv, mux, p, path[:i])
+					if leaf := p.match(path[i:], rv, mux, fold); leaf != nil {
+						return leaf
 					}
-					return
+					resetParams(*rv, l)
+				}
+			}
 
-				case matchesAny:
-					if rv == nil {
-						rv = mux.pool.Get().(*requestVars)
-						rv.params = rv.params[0:0]
-					}
-					// save param value
-					i := len(rv.params)
-					rv.params = rv.params[:i+1] // expand slice within preallocated capacity
-					rv.params[i].key = WildcardParam
-					rv.params[i].value = path[1:]
-					handler = n.handler
-					return
+			if end > 0 && p.accepts(path[:end], mux) {
+				l := paramsLen(*rv)
+				addParam(rv, mux, p, path[:end])
+
+				// we need to go deeper!
+				if n.catchAll == nil {
+					path = path[end:]
+					n = p
+					continue walk
+				}
+				if leaf := p.match(path[end:], rv, mux, fold); leaf != nil {
+					return leaf
 				}
+				resetParams(*rv, l)
 			}
+		}
 
-		} else if path == n.path {
-			// We should have reached the node containing the handle.
-			// Check if this node has a handle registered.
-			if n.handler != nil {
-				handler = n.handler
+		if a := n.catchAll; a != nil {
+			// the catch-all is followed by a static suffix eg. /*path/blob
+			// so try to match the suffix, longest catch-all value first
+			if len(a.indices) > 0 {
+				for i := len(path) - 1; i > 0; i-- {
+					if path[i] != slashByte {
+						continue
+					}
+					l := paramsLen(*rv)
+					addParam(rv, mux, a, path[:i])
+					if leaf := a.match(path[i:], rv, mux, fold); leaf != nil {
+						return leaf
+					}
+					resetParams(*rv, l)
+				}
+			}
+			if a.handler != nil {
+				addParam(rv, mux, a, path)
+				return a
 			}
 		}
+
 		// Nothing found
-		return
+		return nil
+	}
+}
+
+// hasIndex returns if a static child begins with the byte, ignoring ASCII case when fold is set
+func hasIndex(indices string, c byte, fold bool) bool {
+	if strings.IndexByte(indices, c) != -1 {
+		return true
+	}
+	if fold && c|0x20 >= 'a' && c|0x20 <= 'z' {
+		return strings.IndexByte(indices, c^0x20) != -1
+	}
+	return false
+}
+
+// accepts returns if the param value satisfies the param node's constraint, if any
+func (n *node) accepts(value string, mux *Mux) bool {
+	if n.constraint == nil {
+		return true
+	}
+	if mux.useRawPath {
+		value = unescapeParam(value)
+	}
+	return n.constraint(value)
+}
+
+// handle returns the handler of the matched node, that responding while
+// disabled when taken out of service
+func (n *node) handle() http.HandlerFunc {
+	if n.disabled != nil {
+		return n.disabled
+	}
+	return n.handler
+}
+
+// addParam saves the value of the param or catch-all node, acquiring the request vars if not already
+func addParam(rv **requestVars, mux *Mux, n *node, value string) {
+	if *rv == nil {
+		*rv = mux.getRequestVars()
+	}
+	if mux.useRawPath {
+		value = unescapeParam(value)
+	}
+	(*rv).params = append((*rv).params, urlParam{key: n.key, value: value, catchAll: n.nType == matchesAny})
+}
+
+func paramsLen(rv *requestVars) int {
+	if rv == nil {
+		return 0
+	}
+	return len(rv.params)
+}
+
+// resetParams removes the params saved while walking a branch that didn't match
+func resetParams(rv *requestVars, l int) {
+	if rv != nil {
+		rv.params = rv.params[:l]
 	}
 }
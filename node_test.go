@@ -29,10 +29,11 @@ func TestAddChain(t *testing.T) {
 func TestBadWildcard(t *testing.T) {
 
 	p := New()
-	PanicMatches(t, func() { p.Get("/test/:test*test", defaultHandler) }, "only one wildcard per path segment is allowed, has: ':test*test' in path '/test/:test*test'")
+	PanicMatches(t, func() { p.Get("/test/:test*test", defaultHandler) }, "wildcards must be separated by a static literal, has: ':test*test' in path '/test/:test*test'")
+	PanicMatches(t, func() { p.Get("/test/:first:last", defaultHandler) }, "wildcards must be separated by a static literal, has: ':first:last' in path '/test/:first:last'")
 
 	p.Get("/users/:id/contact-info/:cid", defaultHandler)
-	PanicMatches(t, func() { p.Get("/users/:id/*", defaultHandler) }, "wildcard route '*' conflicts with existing children in path '/users/:id/*'")
+	PanicMatches(t, func() { p.Get("/users/:id/contact-info/*", defaultHandler) }, "path segment '*' conflicts with existing wildcard ':cid' in path '/users/:id/contact-info/*'")
 	PanicMatches(t, func() { p.Get("/admin/:/", defaultHandler) }, "wildcards must be named with a non-empty name in path '/admin/:/'")
 	PanicMatches(t, func() { p.Get("/admin/events*", defaultHandler) }, "no / before catch-all in path '/admin/events*'")
 
@@ -84,3 +85,252 @@ func TestBadRoutes(t *testing.T) {
 
 	PanicMatches(t, func() { p.Get("/users//:id", defaultHandler) }, "Bad path '/users//:id' contains duplicate // at index:6")
 }
+
+func TestRoutePattern(t *testing.T) {
+	p := New()
+
+	patternHandler := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte(RequestVars(r).Pattern())); err != nil {
+			panic(err)
+		}
+	}
+
+	p.Get("/", patternHandler)
+	p.Get("/users", patternHandler)
+	p.Get("/users/:id", patternHandler)
+	p.Get("/users/:id/profile", patternHandler)
+	p.Get("/static/*", patternHandler)
+	p.Group("/org/:org").Get("/members/:id", patternHandler)
+	p.Register404(patternHandler)
+
+	tests := []struct {
+		url     string
+		pattern string
+	}{
+		{"/", "/"},
+		{"/users", "/users"},
+		{"/users/13", "/users/:id"},
+		{"/users/13/profile", "/users/:id/profile"},
+		{"/static/css/main.css", "/static/*"},
+		{"/org/acme/members/7", "/org/:org/members/:id"},
+		{"/users/13/unknown", ""},
+	}
+
+	for _, tt := range tests {
+		_, body := request(http.MethodGet, tt.url, p)
+		Equal(t, body, tt.pattern)
+	}
+}
+
+func TestStaticAndParamSegments(t *testing.T) {
+
+	routeHandler := func(w http.ResponseWriter, r *http.Request) {
+		rv := RequestVars(r)
+		if _, err := w.Write([]byte(rv.Pattern() + "|" + rv.URLParam("id") + "|" + rv.URLParam("action") + "|" + rv.URLParam(WildcardParam))); err != nil {
+			panic(err)
+		}
+	}
+
+	p := New()
+	p.Get("/users/new", routeHandler)
+	p.Get("/users/:id", routeHandler)
+	p.Get("/users/:id/edit", routeHandler)
+	p.Get("/users/:id/:action", routeHandler)
+	p.Get("/users/new/:action", routeHandler)
+	p.Get("/users/newest", routeHandler)
+	p.Get("/files/latest/*", routeHandler)
+	p.Get("/files/*", routeHandler)
+	p.Get("/files/static", routeHandler)
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/users/new", http.StatusOK, "/users/new|||"},
+		{"/users/newest", http.StatusOK, "/users/newest|||"},
+		{"/users/ne", http.StatusOK, "/users/:id|ne||"},
+		{"/users/newer", http.StatusOK, "/users/:id|newer||"},
+		{"/users/13", http.StatusOK, "/users/:id|13||"},
+		{"/users/13/edit", http.StatusOK, "/users/:id/edit|13||"},
+		{"/users/13/delete", http.StatusOK, "/users/:id/:action|13|delete|"},
+		{"/users/new/edit", http.StatusOK, "/users/new/:action||edit|"},
+		{"/users/newest/edit", http.StatusOK, "/users/:id/edit|newest||"},
+		{"/users/newest/delete", http.StatusOK, "/users/:id/:action|newest|delete|"},
+		{"/users/13/delete/more", http.StatusNotFound, ""},
+		{"/files/static", http.StatusOK, "/files/static|||"},
+		{"/files/abc", http.StatusOK, "/files/*|||abc"},
+		{"/files/13/abc", http.StatusOK, "/files/*|||13/abc"},
+		{"/files/latest/abc", http.StatusOK, "/files/latest/*|||abc"},
+		{"/files/latest", http.StatusOK, "/files/*|||latest"},
+		{"/files/", http.StatusOK, "/files/*|||"},
+	}
+
+	for _, tt := range tests {
+		code, body := request(http.MethodGet, tt.url, p)
+		Equal(t, code, tt.code)
+		if tt.code == http.StatusOK {
+			Equal(t, body, tt.body)
+		}
+	}
+}
+
+func TestParamsWithinSegment(t *testing.T) {
+
+	paramsHandler := func(keys ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			rv := RequestVars(r)
+			var s string
+			for _, k := range keys {
+				s += k + "=" + rv.URLParam(k) + ";"
+			}
+			if _, err := w.Write([]byte(s)); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	p := New()
+	p.Get("/files/:name.:ext", paramsHandler("name", "ext")).Name("file")
+	p.Get("/files/:name", paramsHandler("name"))
+	p.Get("/files/:name/raw", paramsHandler("name"))
+	p.Get("/v:major.:minor/status", paramsHandler("major", "minor"))
+	p.Get("/img/:w-x-:h.png", paramsHandler("w", "h"))
+	p.Get("/dates/:y<int>-:m<int>-:d<int>", paramsHandler("y", "m", "d"))
+	p.Get("/dates/:y<int>", paramsHandler("y"))
+	p.Get("/pkg/:name@:version", paramsHandler("name", "version"))
+
+	// a param that's the only wildcard in it's segment is named up to the next '/'
+	// as it always has been, a constraint ends it's name allowing a static suffix
+	p.Get("/users/:user-id", paramsHandler("user-id"))
+	p.Get("/data/:file.json", paramsHandler("file.json"))
+	p.Get("/docs/:name<[a-z]+>.json", paramsHandler("name"))
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/files/archive.tar.gz", http.StatusOK, "name=archive;ext=tar.gz;"},
+		{"/files/readme.md", http.StatusOK, "name=readme;ext=md;"},
+		{"/files/readme", http.StatusOK, "name=readme;"},
+		{"/files/readme.", http.StatusOK, "name=readme.;"},
+		{"/files/readme.md/raw", http.StatusOK, "name=readme.md;"},
+		{"/v1.2/status", http.StatusOK, "major=1;minor=2;"},
+		{"/v1/status", http.StatusNotFound, ""},
+		{"/img/100-x-200.png", http.StatusOK, "w=100;h=200;"},
+		{"/img/1-0-x-200.png", http.StatusOK, "w=1-0;h=200;"},
+		{"/img/100-200.png", http.StatusNotFound, ""},
+		{"/img/100-x-200.jpg", http.StatusNotFound, ""},
+		{"/dates/2024-01-02", http.StatusOK, "y=2024;m=01;d=02;"},
+		{"/dates/2024", http.StatusOK, "y=2024;"},
+		{"/dates/2024-Jan-02", http.StatusNotFound, ""},
+		{"/pkg/@scope@1.0", http.StatusOK, "name=@scope;version=1.0;"},
+		{"/users/13", http.StatusOK, "user-id=13;"},
+		{"/data/report.csv", http.StatusOK, "file.json=report.csv;"},
+		{"/docs/intro.json", http.StatusOK, "name=intro;"},
+		{"/docs/intro.yaml", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		code, body := request(http.MethodGet, tt.url, p)
+		Equal(t, code, tt.code)
+		if tt.code == http.StatusOK {
+			Equal(t, body, tt.body)
+		}
+	}
+
+	u, err := p.URL("file", "name", "my file", "ext", "txt")
+	Equal(t, err, nil)
+	Equal(t, u, "/files/my%20file.txt")
+}
+
+func TestNamedAndMidPathCatchAll(t *testing.T) {
+
+	paramsHandler := func(keys ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			rv := RequestVars(r)
+			s := rv.Pattern() + "|"
+			for _, k := range keys {
+				s += k + "=" + rv.URLParam(k) + ";"
+			}
+			if _, err := w.Write([]byte(s)); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	p := New()
+	p.Get("/static/*filepath", paramsHandler("filepath", WildcardParam))
+	p.Get("/wild/*wildcard", paramsHandler("wildcard", WildcardParam))
+	p.Get("/repos/:owner/*path/blob", paramsHandler("owner", "path", WildcardParam)).Name("blob")
+	p.Get("/repos/:owner/*path/blob/:sha", paramsHandler("owner", "path", "sha"))
+	p.Get("/repos/:owner/*path/tree", paramsHandler("owner", "path"))
+	p.Get("/repos/:owner/*path", paramsHandler("owner", "path"))
+	p.Get("/repos/:owner/settings", paramsHandler("owner"))
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/static/css/main.css", http.StatusOK, "/static/*filepath|filepath=css/main.css;*wildcard=css/main.css;"},
+		{"/static/", http.StatusOK, "/static/*filepath|filepath=;*wildcard=;"},
+		{"/wild/a/b", http.StatusOK, "/wild/*wildcard|wildcard=;*wildcard=a/b;"},
+		{"/repos/go/a/b/blob", http.StatusOK, "/repos/:owner/*path/blob|owner=go;path=a/b;*wildcard=a/b;"},
+		{"/repos/go/a/blob/b/blob", http.StatusOK, "/repos/:owner/*path/blob|owner=go;path=a/blob/b;*wildcard=a/blob/b;"},
+		{"/repos/go/a/b/blob/1234", http.StatusOK, "/repos/:owner/*path/blob/:sha|owner=go;path=a/b;sha=1234;"},
+		{"/repos/go/a/b/tree", http.StatusOK, "/repos/:owner/*path/tree|owner=go;path=a/b;"},
+		{"/repos/go/blob", http.StatusOK, "/repos/:owner/*path|owner=go;path=blob;"},
+		{"/repos/go/a/b/blob/", http.StatusOK, "/repos/:owner/*path|owner=go;path=a/b/blob/;"},
+		{"/repos/go/settings", http.StatusOK, "/repos/:owner/settings|owner=go;"},
+		{"/repos/go/settings/more", http.StatusOK, "/repos/:owner/*path|owner=go;path=settings/more;"},
+	}
+
+	for _, tt := range tests {
+		code, body := request(http.MethodGet, tt.url, p)
+		Equal(t, code, tt.code)
+		if tt.code == http.StatusOK {
+			Equal(t, body, tt.body)
+		}
+	}
+
+	u, err := p.URL("blob", "owner", "go", "path", "a b/c")
+	Equal(t, err, nil)
+	Equal(t, u, "/repos/go/a%20b/c/blob")
+
+	p2 := New()
+	p2.Get("/only/*path/blob", defaultHandler)
+	code, _ := request(http.MethodGet, "/only/a/b", p2)
+	Equal(t, code, http.StatusNotFound)
+
+	PanicMatches(t, func() { p.Get("/repos/:owner/*file", defaultHandler) }, "path segment '*file' conflicts with existing wildcard '*path' in path '/repos/:owner/*file'")
+	PanicMatches(t, func() { p.Get("/dup/:path/*path", defaultHandler) }, "Duplicate param name '*path' detected for route '/dup/:path/*path'")
+}
+
+func BenchmarkFind(b *testing.B) {
+
+	p := New()
+	p.Get("/", defaultHandler)
+	p.Get("/users/:user", defaultHandler)
+	p.Get("/users/:user/repos", defaultHandler)
+	p.Get("/repos/:owner/:repo", defaultHandler)
+	p.Get("/repos/:owner/:repo/issues", defaultHandler)
+	p.Get("/repos/:owner/:repo/issues/:number", defaultHandler)
+	p.Get("/repos/:owner/:repo/pulls", defaultHandler)
+	p.Get("/repos/:owner/:repo/contents/*path", defaultHandler)
+	p.Get("/search/repositories", defaultHandler)
+
+	tree := p.served().trees[http.MethodGet]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		leaf, rv := tree.find("/repos/me/pure/issues", nil, p)
+		if leaf == nil {
+			b.Fatal("no route found")
+		}
+		p.pool.Put(rv)
+	}
+}
@@ -0,0 +1,357 @@
+package pure
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The route metadata keys describing the route in the generated OpenAPI document,
+// set using WithMetadata or the options of the same name eg. WithSummary
+const (
+	MetaSummary     = "openapi.summary"     // string
+	MetaDescription = "openapi.description" // string
+	MetaTags        = "openapi.tags"        // []string
+	MetaRequest     = "openapi.request"     // value of the request type
+	MetaResponses   = "openapi.responses"   // map[int]interface{} of status code to value of the response type
+	MetaExclude     = "openapi.exclude"     // bool
+)
+
+// OpenAPIInfo is the information about the API in the generated OpenAPI document
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// WithSummary sets the summary of the route in the generated OpenAPI document
+func WithSummary(summary string) RouteOption {
+	return WithMetadata(MetaSummary, summary)
+}
+
+// WithDescription sets the description of the route in the generated OpenAPI document
+func WithDescription(description string) RouteOption {
+	return WithMetadata(MetaDescription, description)
+}
+
+// WithTags adds the tags to the route in the generated OpenAPI document
+func WithTags(tags ...string) RouteOption {
+	return func(o *RouteOptions) {
+		existing, _ := o.Metadata[MetaTags].([]string)
+		WithMetadata(MetaTags, append(existing[:len(existing):len(existing)], tags...))(o)
+	}
+}
+
+// WithRequestType declares the type of the route's request, of the value provided, whose
+// schema is reflected into the generated OpenAPI document; as the request body or, for
+// GET, HEAD and DELETE routes, the query params using the field's form tags
+// eg. pure.WithRequestType(CreateUser{})
+func WithRequestType(v interface{}) RouteOption {
+	return WithMetadata(MetaRequest, v)
+}
+
+// WithResponseType declares the type of the route's response for the status code, of the
+// value provided, whose schema is reflected into the generated OpenAPI document, nil for
+// a response without a body eg. pure.WithResponseType(http.StatusOK, User{})
+func WithResponseType(status int, v interface{}) RouteOption {
+	return func(o *RouteOptions) {
+		responses, _ := o.Metadata[MetaResponses].(map[int]interface{})
+		c := make(map[int]interface{}, len(responses)+1)
+		for code, r := range responses {
+			c[code] = r
+		}
+		c[status] = v
+		WithMetadata(MetaResponses, c)(o)
+	}
+}
+
+// WithoutOpenAPI excludes the route from the generated OpenAPI document
+func WithoutOpenAPI() RouteOption {
+	return WithMetadata(MetaExclude, true)
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components *openAPIComponents                      `json:"components,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *schema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+// openAPIMethods are the HTTP methods OpenAPI describes operations for, by method
+var openAPIMethods = map[string]string{
+	http.MethodDelete:  "delete",
+	http.MethodGet:     "get",
+	http.MethodHead:    "head",
+	http.MethodOptions: "options",
+	http.MethodPatch:   "patch",
+	http.MethodPost:    "post",
+	http.MethodPut:     "put",
+	http.MethodTrace:   "trace",
+}
+
+// OpenAPI generates an OpenAPI 3.1 JSON document describing the routes being served. The
+// routes registered using Host, for CONNECT or non standard methods, or excluded using
+// WithoutOpenAPI are omitted. Routes are described using their metadata, see WithSummary,
+// WithTags, WithRequestType and WithResponseType, and their path params from their pattern.
+func (p *Mux) OpenAPI(info OpenAPIInfo) ([]byte, error) {
+	return json.Marshal(p.openAPI(p.served(), info))
+}
+
+// OpenAPIYAML is the same as OpenAPI except the document is generated as YAML
+func (p *Mux) OpenAPIYAML(info OpenAPIInfo) ([]byte, error) {
+	b, err := p.OpenAPI(info)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// ServeOpenAPI registers a GET route serving the OpenAPI document, generated on each request
+// so that routes updated while serving are reflected. The document is served as YAML when the
+// path ends with .yaml or .yml, otherwise JSON. The route itself is excluded from the document.
+func (p *Mux) ServeOpenAPI(path string, info OpenAPIInfo) *Route {
+	yaml := strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+
+	return p.Get(path, func(w http.ResponseWriter, r *http.Request) {
+		if !yaml {
+			b, err := p.OpenAPI(info)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			_ = JSONBytes(w, http.StatusOK, b)
+			return
+		}
+
+		b, err := p.OpenAPIYAML(info)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(b)
+	}, WithoutOpenAPI())
+}
+
+// openAPI builds the OpenAPI document describing the table's routes
+func (p *Mux) openAPI(t *table, info OpenAPIInfo) *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info:    info,
+		Paths:   make(map[string]map[string]*openAPIOperation),
+	}
+
+	// names shared by multiple routes eg. registered using Match are suffixed by the method
+	// for the operation ids to be unique
+	names := make(map[string]int)
+	for _, route := range t.routes {
+		if route.Name != blank {
+			names[route.Name]++
+		}
+	}
+
+	s := newSchemas()
+
+	for _, route := range t.routes {
+		method, ok := openAPIMethods[route.Method]
+		if !ok || route.Host != blank {
+			continue
+		}
+		if exclude, _ := route.Metadata[MetaExclude].(bool); exclude {
+			continue
+		}
+
+		path, params := p.openAPIPath(route.Pattern)
+
+		op := &openAPIOperation{
+			OperationID: route.Name,
+			Parameters:  params,
+			Responses:   make(map[string]*openAPIResponse),
+		}
+		if names[route.Name] > 1 {
+			op.OperationID += "." + method
+		}
+		op.Summary, _ = route.Metadata[MetaSummary].(string)
+		op.Description, _ = route.Metadata[MetaDescription].(string)
+		op.Tags, _ = route.Metadata[MetaTags].([]string)
+
+		if req, ok := route.Metadata[MetaRequest]; ok && req != nil {
+			switch route.Method {
+			case http.MethodGet, http.MethodHead, http.MethodDelete:
+				op.Parameters = append(op.Parameters, queryParams(reflect.TypeOf(req), s, params)...)
+			default:
+				op.RequestBody = &openAPIRequestBody{
+					Required: true,
+					Content: map[string]*openAPIMediaType{
+						"application/json": {Schema: s.of(reflect.TypeOf(req))},
+					},
+				}
+			}
+		}
+
+		responses, _ := route.Metadata[MetaResponses].(map[int]interface{})
+		for status, resp := range responses {
+			r := &openAPIResponse{Description: http.StatusText(status)}
+			if resp != nil {
+				r.Content = map[string]*openAPIMediaType{
+					"application/json": {Schema: s.of(reflect.TypeOf(resp))},
+				}
+			}
+			op.Responses[strconv.Itoa(status)] = r
+		}
+		if len(op.Responses) == 0 {
+			op.Responses["200"] = &openAPIResponse{Description: http.StatusText(http.StatusOK)}
+		}
+
+		ops := doc.Paths[path]
+		if ops == nil {
+			ops = make(map[string]*openAPIOperation)
+			doc.Paths[path] = ops
+		}
+		ops[method] = op
+	}
+
+	if len(s.components) > 0 {
+		doc.Components = &openAPIComponents{Schemas: s.components}
+	}
+	return doc
+}
+
+// openAPIPath converts the route pattern to an OpenAPI path template, along with it's
+// path params eg. /users/:id<int>/files/*path becomes /users/{id}/files/{path}
+func (p *Mux) openAPIPath(pattern string) (string, []*openAPIParameter) {
+	var sb strings.Builder
+	var params []*openAPIParameter
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != paramByte && c != wildByte {
+			sb.WriteByte(c)
+			continue
+		}
+
+		end := wildcardEnd(pattern, i)
+		name, constraint := splitParam(pattern[i+1 : end])
+		if c == wildByte {
+			name = strings.TrimPrefix(catchAllKey(name), "*")
+		}
+
+		sb.WriteString("{" + name + "}")
+		params = append(params, &openAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   p.constraintSchema(constraint),
+		})
+		i = end - 1
+	}
+	return sb.String(), params
+}
+
+// constraintSchema returns the schema of a path param with the constraint
+func (p *Mux) constraintSchema(constraint string) *schema {
+	switch constraint {
+	case blank:
+		return &schema{Type: "string"}
+	case "int":
+		return &schema{Type: "integer"}
+	case "uint":
+		zero := 0
+		return &schema{Type: "integer", Minimum: &zero}
+	case "uuid":
+		return &schema{Type: "string", Format: "uuid"}
+	case "alpha":
+		return &schema{Type: "string", Pattern: "^[a-zA-Z]+$"}
+	case "alphanum":
+		return &schema{Type: "string", Pattern: "^[a-zA-Z0-9]+$"}
+	}
+	if _, ok := p.constraints[constraint]; ok {
+		return &schema{Type: "string"}
+	}
+	return &schema{Type: "string", Pattern: "^(?:" + constraint + ")$"}
+}
+
+// queryParams returns the query params of the request type's fields, named using their
+// form tags, excluding those that are path params
+func queryParams(t reflect.Type, s *schemas, path []*openAPIParameter) []*openAPIParameter {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []*openAPIParameter
+
+FIELDS:
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != blank {
+			continue
+		}
+
+		name := f.Name
+		if tag := f.Tag.Get("form"); tag != blank {
+			if tag == "-" {
+				continue
+			}
+			if j := strings.IndexByte(tag, ','); j != -1 {
+				tag = tag[:j]
+			}
+			if tag != blank {
+				name = tag
+			}
+		}
+
+		for _, p := range path {
+			if p.Name == name {
+				continue FIELDS
+			}
+		}
+
+		params = append(params, &openAPIParameter{
+			Name:   name,
+			In:     "query",
+			Schema: s.of(f.Type),
+		})
+	}
+	return params
+}
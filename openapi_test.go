@@ -0,0 +1,158 @@
+package pure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestOpenAPI(t *testing.T) {
+	p := New()
+	p.RegisterConstraint("hex", func(value string) bool { return true })
+
+	p.Get("/users/:id<int>", Typed(typedHandler),
+		WithName("user"),
+		WithSummary("Get a user"),
+		WithDescription("Returns the user"),
+		WithTags("users"),
+		WithTags("public"),
+		WithRequestType(typedRequest{}),
+		WithResponseType(http.StatusOK, typedResponse{}),
+		WithResponseType(http.StatusNotFound, nil),
+	)
+	p.Match([]string{http.MethodPut, http.MethodPatch}, "/users/:id", defaultHandler, WithName("update"), WithRequestType(&typedResponse{}))
+	p.Get("/files/:name.:ext/*path", defaultHandler)
+	p.Get("/colors/:code<hex>/:slug<[a-z]+>", defaultHandler)
+	p.Connect("/tunnel", defaultHandler)
+	p.Get("/internal", defaultHandler, WithoutOpenAPI())
+	p.Host("api.example.com").Get("/hosted", defaultHandler)
+
+	b, err := p.OpenAPI(OpenAPIInfo{Title: "Users", Version: "1.0.0"})
+	Equal(t, err, nil)
+
+	var doc openAPIDocument
+	Equal(t, json.Unmarshal(b, &doc), nil)
+
+	Equal(t, doc.OpenAPI, "3.1.0")
+	Equal(t, doc.Info.Title, "Users")
+	Equal(t, len(doc.Paths), 3)
+
+	get := doc.Paths["/users/{id}"]["get"]
+	Equal(t, get.OperationID, "user")
+	Equal(t, get.Summary, "Get a user")
+	Equal(t, get.Description, "Returns the user")
+	Equal(t, get.Tags, []string{"users", "public"})
+	Equal(t, get.RequestBody, (*openAPIRequestBody)(nil))
+	Equal(t, len(get.Parameters), 3)
+	Equal(t, *get.Parameters[0], openAPIParameter{Name: "id", In: "path", Required: true, Schema: &schema{Type: "integer"}})
+	Equal(t, *get.Parameters[1], openAPIParameter{Name: "name", In: "query", Schema: &schema{Type: "string"}})
+	Equal(t, get.Parameters[2].Name, "limit")
+	Equal(t, len(get.Responses), 2)
+	Equal(t, get.Responses["200"].Content["application/json"].Schema.Ref, "#/components/schemas/typedResponse")
+	Equal(t, *get.Responses["404"], openAPIResponse{Description: "Not Found"})
+
+	put := doc.Paths["/users/{id}"]["put"]
+	Equal(t, put.OperationID, "update.put")
+	Equal(t, put.RequestBody.Content["application/json"].Schema.Ref, "#/components/schemas/typedResponse")
+	Equal(t, *put.Responses["200"], openAPIResponse{Description: "OK"})
+	Equal(t, doc.Paths["/users/{id}"]["patch"].OperationID, "update.patch")
+
+	files := doc.Paths["/files/{name}.{ext}/{path}"]["get"]
+	Equal(t, len(files.Parameters), 3)
+	Equal(t, files.Parameters[2].Name, "path")
+
+	colors := doc.Paths["/colors/{code}/{slug}"]["get"]
+	Equal(t, *colors.Parameters[0].Schema, schema{Type: "string"})
+	Equal(t, *colors.Parameters[1].Schema, schema{Type: "string", Pattern: "^(?:[a-z]+)$"})
+
+	Equal(t, len(doc.Components.Schemas), 1)
+	Equal(t, doc.Components.Schemas["typedResponse"].Required, []string{"id", "name", "limit"})
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	p := New()
+	p.Get("/users/:id", defaultHandler, WithSummary("Get a user: by id"))
+	p.ServeOpenAPI("/openapi.json", OpenAPIInfo{Title: "Users", Version: "1.0.0"})
+	p.ServeOpenAPI("/openapi.yaml", OpenAPIInfo{Title: "Users", Version: "1.0.0"})
+
+	r, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header().Get(httpext.ContentType), httpext.ApplicationJSON)
+	Equal(t, w.Body.String(), `{"openapi":"3.1.0","info":{"title":"Users","version":"1.0.0"},"paths":{"/users/{id}":{"get":{"summary":"Get a user: by id","parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],"responses":{"200":{"description":"OK"}}}}}}`)
+
+	r, _ = http.NewRequest(http.MethodGet, "/openapi.yaml", nil)
+	w = httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header().Get(httpext.ContentType), "application/yaml")
+	Equal(t, w.Body.String(), `info:
+  title: Users
+  version: "1.0.0"
+openapi: "3.1.0"
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - in: path
+          name: id
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+      summary: "Get a user: by id"
+`)
+
+	// routes updated while serving are reflected
+	p.Update(func(g IRouteGroup) {
+		g.Delete("/users/:id", defaultHandler)
+	})
+
+	b, err := p.OpenAPI(OpenAPIInfo{})
+	Equal(t, err, nil)
+
+	var doc openAPIDocument
+	Equal(t, json.Unmarshal(b, &doc), nil)
+	Equal(t, len(doc.Paths["/users/{id}"]), 2)
+}
+
+func TestJSONToYAML(t *testing.T) {
+	b, err := jsonToYAML([]byte(`{"b":[1,"two",{"c":true,"d":null},[],{},["x"]],"a":"yes","e":{}}`))
+	Equal(t, err, nil)
+	Equal(t, string(b), `a: "yes"
+b:
+  - 1
+  - two
+  - c: true
+    d: null
+  - []
+  - {}
+  - - x
+e: {}
+`)
+
+	b, err = jsonToYAML([]byte(`"scalar"`))
+	Equal(t, err, nil)
+	Equal(t, string(b), "scalar\n")
+
+	_, err = jsonToYAML([]byte(`{`))
+	NotEqual(t, err, nil)
+}
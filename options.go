@@ -0,0 +1,91 @@
+package pure
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RouteOption configures a single route when registering it
+// eg. p.Get("/admin", h, pure.WithMiddleware(auth), pure.WithName("admin"))
+type RouteOption func(o *RouteOptions)
+
+// RouteOptions holds the configuration of a single route, set using RouteOption's.
+// It's exported so that options can be defined outside of pure.
+type RouteOptions struct {
+	// Middleware wraps only the route's handler, within the group's middleware
+	Middleware []Middleware
+
+	// Name registers the route under the name, see Route.Name
+	Name string
+
+	// Timeout sets a deadline on the request's context, 0 for none
+	Timeout time.Duration
+
+	// Metadata is arbitrary information about the route, made available
+	// using Mux.Routes eg. for generating documentation
+	Metadata map[string]interface{}
+}
+
+// WithMiddleware adds middleware wrapping only the route's handler,
+// applied within the group's middleware.
+func WithMiddleware(m ...Middleware) RouteOption {
+	return func(o *RouteOptions) {
+		o.Middleware = append(o.Middleware, m...)
+	}
+}
+
+// WithName registers the route under the given name so that it's URL
+// can be built using Mux.URL, see Route.Name
+func WithName(name string) RouteOption {
+	return func(o *RouteOptions) {
+		o.Name = name
+	}
+}
+
+// WithTimeout sets a deadline on the request's context, cancelling it once the
+// timeout elapses. The handler is still responsible for returning once it's
+// context is done; the timeout applies to the route's own middleware too.
+func WithTimeout(timeout time.Duration) RouteOption {
+	return func(o *RouteOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithMetadata sets arbitrary information about the route under the key,
+// available from the route's RouteInfo using Mux.Routes
+func WithMetadata(key string, value interface{}) RouteOption {
+	return func(o *RouteOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]interface{})
+		}
+		o.Metadata[key] = value
+	}
+}
+
+// routeOptions returns the route options with the provided options applied
+func routeOptions(opts []RouteOption) *RouteOptions {
+	o := new(RouteOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// wrap wraps the handler with the route's middleware and timeout
+func (o *RouteOptions) wrap(h http.HandlerFunc) http.HandlerFunc {
+	for i := len(o.Middleware) - 1; i >= 0; i-- {
+		h = o.Middleware[i](h)
+	}
+
+	if o.Timeout > 0 {
+		next := h
+		timeout := o.Timeout
+		h = func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next(w, r.WithContext(ctx))
+		}
+	}
+	return h
+}
@@ -0,0 +1,117 @@
+package pure
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestRouteOptions(t *testing.T) {
+	var order []string
+
+	mw := func(name string) Middleware {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next(w, r)
+			}
+		}
+	}
+
+	p := New()
+	p.Use(mw("mux"))
+
+	var deadline bool
+
+	p.Get("/admin", func(w http.ResponseWriter, r *http.Request) {
+		_, deadline = r.Context().Deadline()
+		_, _ = w.Write([]byte(RequestVars(r).Pattern()))
+	}, WithMiddleware(mw("route1"), mw("route2")), WithName("admin"), WithTimeout(time.Second), WithMetadata("summary", "Admin page"))
+
+	p.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		_, deadline = r.Context().Deadline()
+		_, _ = w.Write([]byte(RequestVars(r).URLParam("id")))
+	})
+
+	code, body := request(http.MethodGet, "/admin", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "/admin")
+	Equal(t, deadline, true)
+	Equal(t, order, []string{"mux", "route1", "route2"})
+
+	order = nil
+
+	code, body = request(http.MethodGet, "/users/13", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "13")
+	Equal(t, deadline, false)
+	Equal(t, order, []string{"mux"})
+
+	url, err := p.URL("admin")
+	Equal(t, err, nil)
+	Equal(t, url, "/admin")
+
+	routes := p.Routes()
+	Equal(t, len(routes), 2)
+	Equal(t, routes[0].Name, "admin")
+	Equal(t, routes[0].Middleware, 3)
+	Equal(t, routes[0].Metadata["summary"], "Admin page")
+	Equal(t, len(routes[1].Metadata), 0)
+
+	// options apply to every method of Any and Match
+	p.Match([]string{http.MethodGet, http.MethodPost}, "/match", defaultHandler, WithName("match"), WithMetadata("tag", "match"))
+
+	routes = p.Routes()
+	Equal(t, len(routes), 4)
+	for _, info := range routes[2:] {
+		Equal(t, info.Name, "match")
+		Equal(t, info.Metadata["tag"], "match")
+	}
+
+	// name conflicts leave the routes unchanged
+	_, err = p.TryHandle(http.MethodGet, "/other", defaultHandler, WithName("admin"))
+	NotEqual(t, err, nil)
+	Equal(t, err.Error(), "Route name 'admin' is already registered for path '/admin'")
+	Equal(t, len(p.Routes()), 4)
+
+	code, _ = request(http.MethodGet, "/other", p)
+	Equal(t, code, http.StatusNotFound)
+
+	PanicMatches(t, func() { p.Get("/other", defaultHandler, WithName("admin")) }, "Route name 'admin' is already registered for path '/admin'")
+}
+
+func TestWithTimeout(t *testing.T) {
+	p := New()
+	p.Get("/slow/:ms", func(w http.ResponseWriter, r *http.Request) {
+		ms, _ := strconv.Atoi(RequestVars(r).URLParam("ms"))
+
+		select {
+		case <-r.Context().Done():
+			Equal(t, r.Context().Err(), context.DeadlineExceeded)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case <-time.After(time.Duration(ms) * time.Millisecond):
+			w.WriteHeader(http.StatusOK)
+		}
+	}, WithTimeout(20*time.Millisecond))
+
+	code, _ := request(http.MethodGet, "/slow/1", p)
+	Equal(t, code, http.StatusOK)
+
+	code, _ = request(http.MethodGet, "/slow/1000", p)
+	Equal(t, code, http.StatusServiceUnavailable)
+}
@@ -0,0 +1,140 @@
+package pure
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Policy determines how a request, whose path doesn't match any route, is handled when
+// it would match a route with or without the trailing slash or when ignoring case.
+type Policy uint8
+
+const (
+	// PolicyStrict doesn't try to match the path, leaving it to the 404/405 handling
+	PolicyStrict Policy = iota
+
+	// PolicyRedirect redirects to the path of the matching route, as registered
+	PolicyRedirect
+
+	// PolicyMatch serves the matching route, as if requested, without redirecting
+	PolicyMatch
+)
+
+// SetTrailingSlashPolicy sets how a request, whose path doesn't match any route,
+// is handled when it matches a route with or without the trailing slash.
+// default PolicyRedirect
+func (p *Mux) SetTrailingSlashPolicy(policy Policy) {
+	p.trailingSlashPolicy = policy
+}
+
+// SetCasePolicy sets how a request, whose path doesn't match any route, is handled
+// when it matches a route when ignoring case; when redirecting, the static segments
+// take the case they were registered with and param values the case requested.
+// default PolicyRedirect
+func (p *Mux) SetCasePolicy(policy Policy) {
+	p.casePolicy = policy
+}
+
+// SetRedirectCode sets the status code used when redirecting to a fixed path eg. 308
+// http.StatusPermanentRedirect. When 0, the default, 301 is used for GET requests and
+// 308 for all other request methods.
+func (p *Mux) SetRedirectCode(code int) {
+	p.redirectCode = code
+}
+
+// fix tries to fix the path not matching any route according to the clean path, trailing
+// slash and case policies; in that order, each being tried on the path fixed so far. It returns
+// the redirecting handler, or when matched without redirecting the route's handler and pattern,
+// along with the request vars, otherwise nil.
+func (p *Mux) fix(tree *node, r *http.Request, path string, rv *requestVars) (http.HandlerFunc, string, *requestVars) {
+	l := paramsLen(rv)
+
+	var leaf *node
+
+	// once cleaned the request is always redirected
+	var cleaned bool
+
+	if p.cleanPath {
+		if cp := cleanPath(path); cp != path {
+			if leaf, rv = tree.find(cp, rv, p); leaf != nil {
+				resetParams(rv, l)
+				return p.redirectTo(r, cp), blank, rv
+			}
+			path = cp
+			cleaned = true
+		}
+	}
+
+	if len(path) <= 1 {
+		return nil, blank, rv
+	}
+
+	ts := path + basePath
+	if path[len(path)-1] == slashByte {
+		ts = path[:len(path)-1]
+	}
+
+	if p.trailingSlashPolicy != PolicyStrict {
+		if leaf, rv = tree.find(ts, rv, p); leaf != nil {
+			if cleaned || p.trailingSlashPolicy == PolicyRedirect {
+				resetParams(rv, l)
+				return p.redirectTo(r, ts), blank, rv
+			}
+			return leaf.handle(), leaf.pattern, rv
+		}
+	}
+
+	if p.casePolicy == PolicyStrict {
+		return nil, blank, rv
+	}
+
+	redirect := cleaned || p.casePolicy == PolicyRedirect
+
+	if leaf, rv = tree.findFold(path, rv, p); leaf == nil && p.trailingSlashPolicy != PolicyStrict {
+		leaf, rv = tree.findFold(ts, rv, p)
+		redirect = redirect || p.trailingSlashPolicy == PolicyRedirect
+	}
+
+	if leaf == nil {
+		return nil, blank, rv
+	}
+	if !redirect {
+		return leaf.handle(), leaf.pattern, rv
+	}
+	h := p.redirectTo(r, p.canonicalPath(leaf.pattern, rv, l))
+	resetParams(rv, l)
+	return h, blank, rv
+}
+
+// canonicalPath returns the path of the matched route, as registered, with the matched param
+// values, excluding those before l eg. host params.
+func (p *Mux) canonicalPath(pattern string, rv *requestVars, l int) string {
+	var params []string
+	if rv != nil {
+		params = make([]string, 0, (len(rv.params)-l)*2)
+		for _, param := range rv.params[l:] {
+			params = append(params, param.key, param.value)
+		}
+	}
+
+	// the values are escaped
+	path, _ := buildURL(pattern, params)
+	if !p.useRawPath {
+		path, _ = url.PathUnescape(path)
+	}
+	return path
+}
+
+// redirectTo returns the handler redirecting to the request's URL with it's path
+// replaced, which is escaped when using the raw path.
+func (p *Mux) redirectTo(r *http.Request, path string) http.HandlerFunc {
+	u := *r.URL
+	if p.useRawPath {
+		u.RawPath = path
+		u.Path, _ = url.PathUnescape(path)
+	} else {
+		u.Path = path
+		u.RawPath = blank
+	}
+	return p.redirect(r.Method, u.String())
+}
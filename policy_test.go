@@ -0,0 +1,163 @@
+package pure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestPolicies(t *testing.T) {
+
+	patternHandler := func(w http.ResponseWriter, r *http.Request) {
+		rv := RequestVars(r)
+		if _, err := w.Write([]byte(rv.Pattern() + "|" + rv.URLParam("ID") + "|" + rv.URLParam("path"))); err != nil {
+			panic(err)
+		}
+	}
+
+	p := New()
+	p.Get("/API/Users/:ID", patternHandler)
+	p.Get("/API/Users/:ID/Files/*path", patternHandler)
+	p.Get("/home/", patternHandler)
+	p.Get("/about", patternHandler)
+	p.Get("/Abc", patternHandler)
+	p.Get("/aBd/", patternHandler)
+	p.Post("/about", patternHandler)
+
+	hf := p.Serve()
+
+	type test struct {
+		method   string
+		url      string
+		code     int
+		body     string
+		location string
+	}
+
+	run := func(tests []test) {
+		for _, tt := range tests {
+			r, _ := http.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+			hf.ServeHTTP(w, r)
+			Equal(t, w.Code, tt.code)
+			Equal(t, w.Header().Get("Location"), tt.location)
+			if tt.code == http.StatusOK {
+				Equal(t, w.Body.String(), tt.body)
+			}
+		}
+	}
+
+	// defaults redirect both to the registered case
+	run([]test{
+		{http.MethodGet, "/API/Users/Joey", http.StatusOK, "/API/Users/:ID|Joey|", ""},
+		{http.MethodGet, "/API/Users/Joey/", http.StatusMovedPermanently, "", "/API/Users/Joey"},
+		{http.MethodGet, "/api/users/Joey?a=b", http.StatusMovedPermanently, "", "/API/Users/Joey?a=b"},
+		{http.MethodGet, "/api/users/Joey/", http.StatusMovedPermanently, "", "/API/Users/Joey"},
+		{http.MethodGet, "/api/users/Joey/files/a%20b/C", http.StatusMovedPermanently, "", "/API/Users/Joey/Files/a%20b/C"},
+		{http.MethodGet, "/HOME", http.StatusMovedPermanently, "", "/home/"},
+		{http.MethodGet, "/abc", http.StatusMovedPermanently, "", "/Abc"},
+		{http.MethodGet, "/abd", http.StatusMovedPermanently, "", "/aBd/"},
+		{http.MethodPost, "/About/", http.StatusPermanentRedirect, "", "/about"},
+		{http.MethodGet, "/contact", http.StatusNotFound, "", ""},
+	})
+
+	// matched without redirecting
+	p.SetTrailingSlashPolicy(PolicyMatch)
+	p.SetCasePolicy(PolicyMatch)
+
+	run([]test{
+		{http.MethodGet, "/API/Users/Joey/", http.StatusOK, "/API/Users/:ID|Joey|", ""},
+		{http.MethodGet, "/api/users/Joey", http.StatusOK, "/API/Users/:ID|Joey|", ""},
+		{http.MethodGet, "/api/users/Joey/", http.StatusOK, "/API/Users/:ID|Joey|", ""},
+		{http.MethodGet, "/api/users/Joey/files/a/B", http.StatusOK, "/API/Users/:ID/Files/*path|Joey|a/B", ""},
+		{http.MethodGet, "/home", http.StatusOK, "/home/||", ""},
+		{http.MethodPost, "/ABOUT/", http.StatusOK, "/about||", ""},
+	})
+
+	// independently
+	p.SetTrailingSlashPolicy(PolicyRedirect)
+
+	run([]test{
+		{http.MethodGet, "/API/Users/Joey/", http.StatusMovedPermanently, "", "/API/Users/Joey"},
+		{http.MethodGet, "/api/users/Joey", http.StatusOK, "/API/Users/:ID|Joey|", ""},
+		{http.MethodGet, "/api/users/Joey/", http.StatusMovedPermanently, "", "/API/Users/Joey"},
+	})
+
+	p.SetTrailingSlashPolicy(PolicyStrict)
+	p.SetCasePolicy(PolicyRedirect)
+	p.SetRedirectCode(http.StatusPermanentRedirect)
+
+	run([]test{
+		{http.MethodGet, "/API/Users/Joey/", http.StatusNotFound, "", ""},
+		{http.MethodGet, "/api/users/Joey", http.StatusPermanentRedirect, "", "/API/Users/Joey"},
+		{http.MethodGet, "/api/users/Joey/", http.StatusNotFound, "", ""},
+		{http.MethodGet, "/home", http.StatusNotFound, "", ""},
+	})
+
+	p.SetCasePolicy(PolicyStrict)
+	p.SetTrailingSlashPolicy(PolicyMatch)
+
+	run([]test{
+		{http.MethodGet, "/API/Users/Joey/", http.StatusOK, "/API/Users/:ID|Joey|", ""},
+		{http.MethodGet, "/api/users/Joey", http.StatusNotFound, "", ""},
+	})
+
+	// cleaned paths are always redirected
+	p.SetCleanPath(true)
+	p.SetCasePolicy(PolicyMatch)
+	p.SetRedirectCode(0)
+
+	run([]test{
+		{http.MethodGet, "/API//Users/Joey/", http.StatusMovedPermanently, "", "/API/Users/Joey"},
+		{http.MethodGet, "/api/./users/Joey", http.StatusMovedPermanently, "", "/API/Users/Joey"},
+	})
+
+	// the previous combined option sets both policies
+	p.SetRedirectTrailingSlash(false)
+	Equal(t, p.trailingSlashPolicy, PolicyStrict)
+	Equal(t, p.casePolicy, PolicyStrict)
+
+	p.SetRedirectTrailingSlash(true)
+	Equal(t, p.trailingSlashPolicy, PolicyRedirect)
+	Equal(t, p.casePolicy, PolicyRedirect)
+}
+
+func TestPoliciesUseRawPath(t *testing.T) {
+
+	p := New()
+	p.UseRawPath(true)
+	p.Get("/Objects/:key", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte(RequestVars(r).URLParam("key"))); err != nil {
+			panic(err)
+		}
+	})
+
+	hf := p.Serve()
+
+	r, _ := http.NewRequest(http.MethodGet, "/objects/A%2FB/", nil)
+	w := httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusMovedPermanently)
+	Equal(t, w.Header().Get("Location"), "/Objects/A%2FB")
+
+	p.SetCasePolicy(PolicyMatch)
+	p.SetTrailingSlashPolicy(PolicyMatch)
+
+	w = httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Body.String(), "A/B")
+}
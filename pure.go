@@ -3,8 +3,8 @@ package pure
 import (
 	"context"
 	"net/http"
-	"strings"
 	"sync"
+	"sync/atomic"
 
 	httpext "github.com/go-playground/pkg/v5/net/http"
 )
@@ -15,12 +15,30 @@ var (
 	}{
 		name: "pure",
 	}
+
+	// optionsAllowed is allowed for paths not matching any route when OPTIONS is automatically handled
+	optionsAllowed = []string{http.MethodOptions}
 )
 
 // Mux is the main request multiplexer
 type Mux struct {
 	routeGroup
-	trees map[string]*node
+
+	// table holds the routes registered on, which is also the one being served
+	// except during Update
+	table *table
+
+	// live holds the *table being served
+	live atomic.Value
+
+	// mu serializes updating the routes once serving
+	mu sync.Mutex
+
+	// constraints holds the named param constraints eg. int in /users/:id<int>
+	constraints map[string]Constraint
+
+	// pre holds the middleware run before the route is looked up, see UsePre
+	pre []Middleware
 
 	// pool is used for reusable request scoped RequestVars content
 	pool sync.Pool
@@ -28,18 +46,32 @@ type Mux struct {
 	http404     http.HandlerFunc // 404 Not Found
 	http405     http.HandlerFunc // 405 Method Not Allowed
 	httpOPTIONS http.HandlerFunc
+	httpError   ErrorHandler // writes the response for errors returned by HandlerFuncE's
 
-	// mostParams used to keep track of the most amount of
-	// params in any URL and this will set the default capacity
-	// of each Params
-	mostParams uint8
-
-	// Enables automatic redirection if the current route can't be matched but a
+	// Determines how a request is handled if the current route can't be matched but a
 	// handler for the path with (without) the trailing slash exists.
-	// For example if /foo/ is requested but a route only exists for /foo, the
+	// For example if /foo/ is requested but a route only exists for /foo, by default the
 	// client is redirected to /foo with http status code 301 for GET requests
-	// and 307 for all other request methods.
-	redirectTrailingSlash bool
+	// and 308 for all other request methods.
+	trailingSlashPolicy Policy
+
+	// Determines how a request is handled if the current route can't be matched
+	// but a handler for the path exists when ignoring case.
+	casePolicy Policy
+
+	// the status code used when redirecting, when 0 301 is used for GET
+	// requests and 308 for all other request methods
+	redirectCode int
+
+	// If enabled, routes are matched against the request's escaped path, rather than the unescaped
+	// path, and each param value is unescaped separately allowing values containing encoded
+	// slashes eg. %2F
+	useRawPath bool
+
+	// Enables automatic redirection to the canonical path if the current route can't
+	// be matched but a handler exists for the path with it's '.' and '..' elements resolved
+	// and repeated slashes removed eg. /api//users/../users/1 is redirected to /api/users/1
+	cleanPath bool
 
 	// If enabled, the router checks if another method is allowed for the
 	// current route, if the current request can not be routed.
@@ -52,16 +84,26 @@ type Mux struct {
 	// if enabled automatically handles OPTION requests; manually configured OPTION
 	// handlers take presidence. default true
 	automaticallyHandleOPTIONS bool
+
+	// if enabled HEAD requests, not matching a HEAD route, are served by the matching
+	// GET route with the body discarded. default false
+	automaticallyHandleHEAD bool
+
+	// if enabled the X-Forwarded-Host header, when present, is used over the request
+	// Host when matching the routes registered using Host. default false
+	trustForwardedHost bool
 }
 
 type urlParam struct {
-	key   string
-	value string
+	key      string
+	value    string
+	catchAll bool
 }
 
 type urlParams []urlParam
 
-// Get returns the URL parameter for the given key, or blank if not found
+// Get returns the URL parameter for the given key, or blank if not found.
+// WildcardParam also returns the value of a named catch-all eg. /static/*filepath
 func (p urlParams) Get(key string) (param string) {
 	for i := 0; i < len(p); i++ {
 		if p[i].key == key {
@@ -69,6 +111,14 @@ func (p urlParams) Get(key string) (param string) {
 			return
 		}
 	}
+	if key == WildcardParam {
+		for i := 0; i < len(p); i++ {
+			if p[i].catchAll {
+				param = p[i].value
+				return
+			}
+		}
+	}
 	return
 }
 
@@ -95,29 +145,38 @@ func New() *Mux {
 		routeGroup: routeGroup{
 			middleware: make([]Middleware, 0),
 		},
-		trees:                      make(map[string]*node),
-		mostParams:                 0,
+		table:                      newTable(),
 		http404:                    default404Handler,
 		http405:                    methodNotAllowedHandler,
 		httpOPTIONS:                automaticOPTIONSHandler,
-		redirectTrailingSlash:      true,
+		httpError:                  defaultErrorHandler,
+		trailingSlashPolicy:        PolicyRedirect,
+		casePolicy:                 PolicyRedirect,
 		handleMethodNotAllowed:     false,
 		automaticallyHandleOPTIONS: false,
 	}
 	p.routeGroup.pure = p
+	p.live.Store(p.table)
+	p.constraints = make(map[string]Constraint, len(defaultConstraints))
+	for name, c := range defaultConstraints {
+		p.constraints[name] = c
+	}
 	p.pool.New = func() interface{} {
-
-		rv := &requestVars{
-			params: make(urlParams, p.mostParams),
+		return &requestVars{
+			parent: context.Background(),
+			params: make(urlParams, p.served().mostParams),
 		}
-
-		rv.ctx = context.WithValue(context.Background(), defaultContextIdentifier, rv)
-
-		return rv
 	}
 	return p
 }
 
+// getRequestVars returns a reset requestVars from the pool
+func (p *Mux) getRequestVars() *requestVars {
+	rv := p.pool.Get().(*requestVars)
+	rv.params = rv.params[0:0]
+	return rv
+}
+
 // Register404 alows for overriding of the not found handler function.
 // NOTE: this is run after not finding a route even after redirecting with the trailing slash
 func (p *Mux) Register404(notFound http.HandlerFunc, middleware ...Middleware) {
@@ -141,11 +200,51 @@ func (p *Mux) RegisterAutomaticOPTIONS(middleware ...Middleware) {
 	p.httpOPTIONS = h
 }
 
+// SetAutomaticHEAD tells pure whether to automatically
+// handle HEAD requests using the matching GET route, discarding
+// the body but keeping the headers, including the Content-Length;
+// manually configured HEAD handlers take precedence. default false
+func (p *Mux) SetAutomaticHEAD(set bool) {
+	p.automaticallyHandleHEAD = set
+}
+
 // SetRedirectTrailingSlash tells pure whether to try
 // and fix a URL by trying to find it
-// lowercase -> with or without slash -> 404
+// with or without slash -> ignoring case -> 404
+// and redirecting to it if found.
+//
+// It sets both the trailing slash and case policies to PolicyRedirect
+// when true, otherwise PolicyStrict; see SetTrailingSlashPolicy and
+// SetCasePolicy to set them independently.
 func (p *Mux) SetRedirectTrailingSlash(set bool) {
-	p.redirectTrailingSlash = set
+	policy := PolicyStrict
+	if set {
+		policy = PolicyRedirect
+	}
+	p.trailingSlashPolicy = policy
+	p.casePolicy = policy
+}
+
+// SetCleanPath tells pure whether to try and fix a URL
+// by cleaning it's path, resolving '.' and '..' elements and
+// removing repeated slashes, and redirect to the cleaned path if
+// found. When enabled it's applied before the trailing slash and
+// case policies, which are then tried on the cleaned path. default false
+func (p *Mux) SetCleanPath(set bool) {
+	p.cleanPath = set
+}
+
+// UseRawPath tells pure whether to match routes against
+// the request's escaped path, r.URL.EscapedPath(), rather
+// than it's unescaped path, r.URL.Path, and unescape each
+// param value separately. This allows param values to contain
+// encoded slashes eg. %2F in /objects/:key. Static segments are
+// then matched as registered without being unescaped.
+// default false
+//
+// NOTE: must be set prior to registering routes.
+func (p *Mux) UseRawPath(set bool) {
+	p.useRawPath = set
 }
 
 // RegisterMethodNotAllowed tells pure whether to
@@ -160,120 +259,272 @@ func (p *Mux) RegisterMethodNotAllowed(middleware ...Middleware) {
 	p.http405 = h
 }
 
+// UsePre adds middleware run for every request before the route is looked up, including
+// those resulting in a 404, 405, OPTIONS or redirect response. Unlike the middleware added
+// using Use, which wraps each route's handler when registered, it can affect which route is
+// matched eg. by rewriting r.URL.Path or overriding the method, or short-circuit the request.
+//
+// NOTE: must be called prior to Serve.
+func (p *Mux) UsePre(m ...Middleware) {
+	p.pre = append(p.pre, m...)
+}
+
 // Serve returns an http.Handler to be used.
 func (p *Mux) Serve() http.Handler {
 	// reserved for any logic that needs to happen before serving starts.
 	// i.e. although this router does not use priority to determine route order
 	// could add sorting of tree nodes here....
-	return http.HandlerFunc(p.serveHTTP)
+	var h http.HandlerFunc = p.serveHTTP
+	for i := len(p.pre) - 1; i >= 0; i-- {
+		h = p.pre[i](h)
+	}
+	return h
 }
 
 // Conforms to the http.Handler interface.
 func (p *Mux) serveHTTP(w http.ResponseWriter, r *http.Request) {
-	tree := p.trees[r.Method]
+	t := p.served()
+	var host, pattern string
 	var h http.HandlerFunc
 	var rv *requestVars
+	var hw *headResponseWriter
+	var tree, leaf *node
+	var allowed []string
 
-	if tree != nil {
-		if h, rv = tree.find(r.URL.Path, p); h == nil {
-			if p.redirectTrailingSlash && len(r.URL.Path) > 1 {
-				// find again all lowercase
-				orig := r.URL.Path
-				lc := strings.ToLower(orig)
-
-				if lc != r.URL.Path {
-
-					if h, _ = tree.find(lc, p); h != nil {
-						r.URL.Path = lc
-						h = p.redirect(r.Method, r.URL.String())
-						r.URL.Path = orig
-						goto END
-					}
-				}
-				if lc[len(lc)-1:] == basePath {
-					lc = lc[:len(lc)-1]
-				} else {
-					lc = lc + basePath
-				}
-				if h, _ = tree.find(lc, p); h != nil {
-					r.URL.Path = lc
-					h = p.redirect(r.Method, r.URL.String())
-					r.URL.Path = orig
-					goto END
-				}
-			}
-		} else {
+	if t.hostTree != nil {
+		host, rv = p.matchHost(t, r)
+	}
+
+	trees := t.routeTrees(host)
+
+	path := r.URL.Path
+	if p.useRawPath {
+		path = r.URL.EscapedPath()
+	}
+
+	if t.rules != nil {
+		var rewritten *http.Request
+		if h, rewritten = p.applyRules(t, r, path); h != nil {
 			goto END
 		}
+		if rewritten != nil {
+			r = rewritten
+			if path = r.URL.Path; p.useRawPath {
+				path = r.URL.EscapedPath()
+			}
+		}
 	}
 
-	if p.automaticallyHandleOPTIONS && r.Method == http.MethodOptions {
-		if r.URL.Path == "*" { // check server-wide OPTIONS
+	tree = trees[r.Method]
 
-			for m := range p.trees {
+	if tree != nil {
+		if leaf, rv = tree.find(path, rv, p); leaf != nil {
+			h, pattern = leaf.handle(), leaf.pattern
+			goto END
+		}
+	}
 
-				if m == http.MethodOptions {
-					continue
-				}
+	// the methods not registered by Any are routed to the handlers mounted using Mount
+	if mounts := t.mounts[host]; mounts != nil && !hasMethod(anyMethods, r.Method) {
+		if leaf, rv = mounts.find(path, rv, p); leaf != nil {
+			h, pattern = leaf.handler, leaf.pattern
+			goto END
+		}
+	}
 
-				w.Header().Add(httpext.Allow, m)
+	if tree != nil {
+		if h, pattern, rv = p.fix(tree, r, path, rv); h != nil {
+			goto END
+		}
+	}
+
+	if p.automaticallyHandleHEAD && r.Method == http.MethodHead {
+		if gtree := trees[http.MethodGet]; gtree != nil {
+			if leaf, rv = gtree.find(path, rv, p); leaf == nil {
+				h, pattern, rv = p.fix(gtree, r, path, rv)
+			} else {
+				h, pattern = leaf.handle(), leaf.pattern
 			}
-		} else {
-			for m, ctree := range p.trees {
-
-				if m == r.Method || m == http.MethodOptions {
-					continue
-				}
-				if h, _ = ctree.find(r.URL.Path, p); h != nil {
-					w.Header().Add(httpext.Allow, m)
-				}
+			if h != nil {
+				hw = &headResponseWriter{ResponseWriter: w}
+				w = hw
+				goto END
 			}
 		}
-		w.Header().Add(httpext.Allow, http.MethodOptions)
+	}
+
+	if p.automaticallyHandleOPTIONS && r.Method == http.MethodOptions {
+		if path == "*" { // check server-wide OPTIONS
+			allowed = t.methods[host].get(p.automaticallyHandleHEAD, true)
+		} else {
+			allowed = p.allowed(t, host, r.Method, path).get(p.automaticallyHandleHEAD, true)
+		}
+		p.allow(w, allowed)
 		h = p.httpOPTIONS
 		goto END
 	}
 
-	if p.handleMethodNotAllowed {
-		var found bool
-		for m, ctree := range p.trees {
-
-			if m == r.Method {
-				continue
-			}
-
-			if h, _ = ctree.find(r.URL.Path, p); h != nil {
-				w.Header().Add(httpext.Allow, m)
-				found = true
+	if h = p.fallback(t.methodNotAllowed, host, path); h != nil || p.handleMethodNotAllowed {
+		if allowed = p.allowed(t, host, r.Method, path).get(p.automaticallyHandleHEAD, false); len(allowed) > 0 {
+			if h == nil {
+				h = p.http405
 			}
-		}
-		if found {
-			h = p.http405
+			p.allow(w, allowed)
 			goto END
 		}
 	}
 	// not found
-	h = p.http404
+	if h = p.fallback(t.notFound, host, path); h == nil {
+		h = p.http404
+	}
 
 END:
 
-	if rv != nil {
+	// static routes, whose pattern is the requested path, are served without storing the
+	// request vars to avoid allocating, RequestVars then returns the path as the pattern;
+	// unless served by a mounted Mux, whose vars would otherwise be returned
+	if rv != nil || pattern != r.URL.Path || r.Context().Value(defaultContextIdentifier) != nil {
+		if rv == nil {
+			rv = p.getRequestVars()
+		}
+		rv.pattern = pattern
+		rv.allowed = allowed
 		rv.formParsed = false
-		// store on context
-		r = r.WithContext(rv.ctx)
+		// layer on top of the incoming context and store on the request, the request
+		// vars are put back into the pool once the handler returns
+		rv.parent = r.Context()
+		r = r.WithContext(rv)
+		defer p.putRequestVars(rv)
+	}
+
+	if hw != nil {
+		defer hw.finish()
 	}
 
 	h(w, r)
+}
+
+// putRequestVars returns the request vars to the pool, releasing the incoming requests context
+func (p *Mux) putRequestVars(rv *requestVars) {
+	rv.parent = context.Background()
+	p.pool.Put(rv)
+}
+
+// allowed returns the methods allowed for the path, those of the methods other than the
+// requested method having a route matching it; nil if none match. The precomputed methods
+// of each matched route's pattern are merged, the routes of the methods already included
+// not being looked up, so only paths matching routes of different patterns allocate.
+func (p *Mux) allowed(t *table, host string, method string, path string) *allowedMethods {
+	trees := t.routeTrees(host)
+
+	var allowed *allowedMethods
+	var merged []string
+
+	for _, m := range t.methods[host].list() {
+		covered := merged
+		if covered == nil {
+			covered = allowed.list()
+		}
+		if m == method || hasMethod(covered, m) {
+			continue
+		}
+		leaf, rv := trees[m].find(path, nil, p)
+		if rv != nil {
+			p.pool.Put(rv)
+		}
+		if leaf == nil {
+			continue
+		}
 
-	if rv != nil {
-		p.pool.Put(rv)
+		a := t.allowed[host][leaf.pattern]
+		switch {
+		case allowed == nil:
+			allowed = a
+		case merged == nil:
+			merged = mergeMethods(allowed.list(), a.list())
+		default:
+			merged = mergeMethods(merged, a.list())
+		}
 	}
+
+	if merged != nil {
+		return newAllowedMethods(merged)
+	}
+	return allowed
+}
+
+// allowedMethods holds sorted methods along with their variants including HEAD and OPTIONS,
+// when automatically handled, so the allowed methods are precomputed as routes are registered
+// and the Allow header is built without allocating.
+type allowedMethods struct {
+	methods     []string
+	head        []string // including HEAD when GET is allowed
+	options     []string // including OPTIONS
+	headOptions []string // including both
+}
+
+func newAllowedMethods(methods []string) *allowedMethods {
+	if len(methods) == 0 {
+		return nil
+	}
+	a := &allowedMethods{methods: methods, head: methods}
+	if hasMethod(methods, http.MethodGet) {
+		a.head = insertMethod(methods, http.MethodHead)
+	}
+	a.options = insertMethod(a.methods, http.MethodOptions)
+	a.headOptions = insertMethod(a.head, http.MethodOptions)
+	return a
+}
+
+// list returns the sorted methods, nil if none
+func (a *allowedMethods) list() []string {
+	if a == nil {
+		return nil
+	}
+	return a.methods
+}
+
+// get returns the sorted methods including HEAD, when GET is allowed, and OPTIONS
+// when automatically handled; the result must not be modified as it's shared
+func (a *allowedMethods) get(head bool, options bool) []string {
+	switch {
+	case a == nil && options:
+		return optionsAllowed
+	case a == nil:
+		return nil
+	case head && options:
+		return a.headOptions
+	case head:
+		return a.head
+	case options:
+		return a.options
+	}
+	return a.methods
+}
+
+// allow sets the Allow header to the allowed methods
+func (p *Mux) allow(w http.ResponseWriter, allowed []string) {
+	for _, m := range allowed {
+		w.Header().Add(httpext.Allow, m)
+	}
+}
+
+// routePath returns the path the route pattern is inserted into the trees as, when
+// not using the raw path the pattern is unescaped
+func (p *Mux) routePath(pattern string) (string, error) {
+	if p.useRawPath {
+		return pattern, nil
+	}
+	return unescape(pattern)
 }
 
 func (p *Mux) redirect(method string, to string) (h http.HandlerFunc) {
-	code := http.StatusMovedPermanently
-	if method != http.MethodGet {
-		code = http.StatusPermanentRedirect
+	code := p.redirectCode
+	if code == 0 {
+		code = http.StatusMovedPermanently
+		if method != http.MethodGet {
+			code = http.StatusPermanentRedirect
+		}
 	}
 	h = func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, to, code)
@@ -2,6 +2,7 @@ package pure
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -9,7 +10,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/go-playground/assert/v2"
 	httpext "github.com/go-playground/pkg/v5/net/http"
@@ -401,17 +404,187 @@ func TestMethodNotAllowed2(t *testing.T) {
 	Equal(t, w.Code, http.StatusMethodNotAllowed)
 
 	allow, ok := w.Header()[httpext.Allow]
+	Equal(t, ok, true)
+	Equal(t, allow, []string{http.MethodGet, http.MethodHead})
+}
 
-	// Sometimes this array is out of order for whatever reason?
-	if allow[0] == http.MethodGet {
-		Equal(t, ok, true)
-		Equal(t, allow[0], http.MethodGet)
-		Equal(t, allow[1], http.MethodHead)
-	} else {
-		Equal(t, ok, true)
-		Equal(t, allow[1], http.MethodGet)
-		Equal(t, allow[0], http.MethodHead)
+func TestAllowedMethods(t *testing.T) {
+	var allowed []string
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		allowed = RequestVars(r).AllowedMethods()
+	}
+	allowedMiddleware := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			next(w, r)
+			allowed = RequestVars(r).AllowedMethods()
+		}
 	}
+
+	p := New()
+	p.RegisterMethodNotAllowed(allowedMiddleware)
+	p.RegisterAutomaticOPTIONS(allowedMiddleware)
+	p.SetAutomaticHEAD(true)
+
+	p.Put("/users/:id", handler)
+	p.Get("/users/:id", handler)
+	p.Delete("/users/:id", handler)
+	p.Handle("PROPFIND", "/users/:id", handler)
+	p.Post("/users", handler)
+
+	for i := 0; i < 10; i++ {
+		r, _ := http.NewRequest(http.MethodPost, "/users/1", nil)
+		w := httptest.NewRecorder()
+		p.serveHTTP(w, r)
+		Equal(t, w.Code, http.StatusMethodNotAllowed)
+		Equal(t, w.Header()[httpext.Allow], []string{http.MethodDelete, http.MethodGet, http.MethodHead, "PROPFIND", http.MethodPut})
+		Equal(t, allowed, []string{http.MethodDelete, http.MethodGet, http.MethodHead, "PROPFIND", http.MethodPut})
+	}
+
+	r, _ := http.NewRequest(http.MethodOptions, "/users/1", nil)
+	w := httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND", http.MethodPut})
+	Equal(t, allowed, []string{http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND", http.MethodPut})
+
+	r, _ = http.NewRequest(http.MethodOptions, "*", nil)
+	w = httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPost, "PROPFIND", http.MethodPut})
+
+	// matched routes have no allowed methods
+	code, _ := request(http.MethodGet, "/users/1", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, len(allowed), 0)
+
+	// removing the only route of a method removes it from the Allow header
+	Equal(t, p.Remove("PROPFIND", "/users/:id"), true)
+
+	r, _ = http.NewRequest(http.MethodPost, "/users/1", nil)
+	w = httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusMethodNotAllowed)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodPut})
+
+	// the allowed methods are precomputed as the routes are updated
+	allocs := testing.AllocsPerRun(100, func() {
+		p.allowed(p.served(), blank, http.MethodPost, "/users/1")
+	})
+	Equal(t, allocs, float64(0))
+
+	p.Update(func(g IRouteGroup) {
+		g.Patch("/users/:id", handler)
+	})
+
+	r, _ = http.NewRequest(http.MethodPost, "/users/1", nil)
+	w = httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusMethodNotAllowed)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodPatch, http.MethodPut})
+
+	// the methods matching the path through different patterns are merged
+	p.Update(func(g IRouteGroup) {
+		g.Post("/users/new", handler)
+		g.Trace("/users/:uid", handler)
+	})
+
+	r, _ = http.NewRequest(http.MethodOptions, "/users/new", nil)
+	w = httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPatch, http.MethodPost, http.MethodPut, http.MethodTrace})
+
+	with := New()
+	with.Get("/users/:id", handler)
+	with.Post("/users/new", handler)
+	with.Put("/users/:uid", handler)
+	p.Swap(with)
+
+	r, _ = http.NewRequest(http.MethodDelete, "/users/new", nil)
+	w = httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusMethodNotAllowed)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut})
+	Equal(t, allowed, []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut})
+
+	r, _ = http.NewRequest(http.MethodDelete, "/users/13", nil)
+	w = httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusMethodNotAllowed)
+	Equal(t, w.Header()[httpext.Allow], []string{http.MethodGet, http.MethodHead, http.MethodPut})
+}
+
+func TestUsePre(t *testing.T) {
+	var pre []string
+
+	p := New()
+	p.Register404(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(r.Header.Get("X-Locale")))
+	})
+
+	// strips the locale prefix
+	p.UsePre(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			pre = append(pre, r.URL.Path)
+			if strings.HasPrefix(r.URL.Path, "/fr/") {
+				r.Header.Set("X-Locale", "fr")
+				r.URL.Path = r.URL.Path[3:]
+			}
+			next(w, r)
+		}
+	})
+
+	// method override and maintenance gate
+	p.UsePre(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/maintenance" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if m := r.Header.Get("X-HTTP-Method-Override"); m != "" && r.Method == http.MethodPost {
+				r.Method = m
+			}
+			next(w, r)
+		}
+	})
+
+	p.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Locale") + RequestVars(r).URLParam("id")))
+	})
+	p.Delete("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(http.MethodDelete))
+	})
+	p.Get("/maintenance", defaultHandler)
+
+	hf := p.Serve()
+
+	tests := []struct {
+		method   string
+		path     string
+		override string
+		code     int
+		body     string
+	}{
+		{http.MethodGet, "/users/1", "", http.StatusOK, "1"},
+		{http.MethodGet, "/fr/users/1", "", http.StatusOK, "fr1"},
+		{http.MethodPost, "/users/1", http.MethodDelete, http.StatusOK, http.MethodDelete},
+		{http.MethodGet, "/maintenance", "", http.StatusServiceUnavailable, ""},
+		{http.MethodGet, "/fr/nope", "", http.StatusNotFound, "fr"},
+	}
+
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.path, nil)
+		if tt.override != "" {
+			r.Header.Set("X-HTTP-Method-Override", tt.override)
+		}
+		w := httptest.NewRecorder()
+		hf.ServeHTTP(w, r)
+		Equal(t, w.Code, tt.code)
+		Equal(t, w.Body.String(), tt.body)
+	}
+	Equal(t, pre, []string{"/users/1", "/fr/users/1", "/users/1", "/maintenance", "/fr/nope"})
 }
 
 func TestAutomaticallyHandleOPTIONS(t *testing.T) {
@@ -441,7 +614,7 @@ func TestAutomaticallyHandleOPTIONS(t *testing.T) {
 	allow, ok := w.Header()[httpext.Allow]
 
 	Equal(t, ok, true)
-	Equal(t, len(allow), 10)
+	Equal(t, allow, []string{http.MethodConnect, http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPatch, http.MethodPost, "PROPFIND", http.MethodPut, http.MethodTrace})
 
 	r, _ = http.NewRequest(http.MethodOptions, "*", nil)
 	w = httptest.NewRecorder()
@@ -558,7 +731,8 @@ func TestBadAdd(t *testing.T) {
 	p.Get("/admin/:id/profile", fn)
 	PanicMatches(t, func() { p.Get("/admin/:admin_id", fn) }, "path segment ':admin_id' conflicts with existing wildcard ':id' in path '/admin/:admin_id'")
 
-	PanicMatches(t, func() { p.Get("/assets/*/test", fn) }, "Character after the * symbol is not permitted, path '/assets/*/test'")
+	PanicMatches(t, func() { p.Get("/assets/*.css", fn) }, "Character after the * symbol is not permitted, path '/assets/*.css'")
+	PanicMatches(t, func() { p.Get("/assets/*file.css", fn) }, "Character after the * symbol is not permitted, path '/assets/*file.css'")
 
 	p.Get("/superhero/*", fn)
 	PanicMatches(t, func() { p.Get("/superhero/:id", fn) }, "path segment '/:id' conflicts with existing wildcard '/*' in path '/superhero/:id'")
@@ -570,6 +744,133 @@ func TestBadAdd(t *testing.T) {
 	PanicMatches(t, func() { p.Get("/supervillain/:id", fn) }, "handlers are already registered for path '/supervillain/:id'")
 }
 
+func TestRequestContext(t *testing.T) {
+
+	type ctxKey struct{}
+
+	var ctxErr error
+
+	p := New()
+	p.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		ctxErr = r.Context().Err()
+		_, deadline := r.Context().Deadline()
+		if _, err := w.Write([]byte(fmt.Sprintf("%s|%v|%v", RequestVars(r).URLParam("id"), r.Context().Value(ctxKey{}), deadline))); err != nil {
+			panic(err)
+		}
+	})
+
+	hf := p.Serve()
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), ctxKey{}, "principal"), time.Minute)
+	cancel()
+
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/users/13", nil)
+	w := httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Body.String(), "13|principal|true")
+	Equal(t, ctxErr, context.Canceled)
+}
+
+func TestPooledRequestContext(t *testing.T) {
+
+	type ctxKey struct{}
+
+	var saved context.Context
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "principal"))
+
+	p := New()
+	p.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		Equal(t, ctx.Value(ctxKey{}), "principal")
+		Equal(t, RequestVars(r).URLParam("id"), "13")
+		Equal(t, RequestVars(r).Pattern(), "/users/:id")
+
+		_, ok := ctx.Deadline()
+		Equal(t, ok, false)
+		Equal(t, ctx.Err(), nil)
+
+		cancel()
+		<-ctx.Done()
+		Equal(t, ctx.Err(), context.Canceled)
+
+		saved = ctx
+	})
+
+	hf := p.Serve()
+
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/users/13", nil)
+	hf.ServeHTTP(httptest.NewRecorder(), r)
+
+	// the pooled context must not be retained, but doing so releases
+	// the incoming requests context rather than panicking
+	Equal(t, saved.Err(), nil)
+	Equal(t, saved.Value(ctxKey{}), nil)
+}
+
+func BenchmarkParamRoute(b *testing.B) {
+
+	p := New()
+	p.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {})
+
+	hf := p.Serve()
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/users/13", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		hf.ServeHTTP(w, r)
+	}
+}
+
+func BenchmarkStaticRoute(b *testing.B) {
+
+	p := New()
+	p.Get("/users/list", func(w http.ResponseWriter, r *http.Request) {})
+
+	hf := p.Serve()
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/users/list", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		hf.ServeHTTP(w, r)
+	}
+}
+
+func TestStaticRouteAllocs(t *testing.T) {
+
+	var pattern string
+
+	p := New()
+	p.Use(defaultMiddleware)
+	p.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+	p.Get("/users/:id", func(w http.ResponseWriter, r *http.Request) {})
+	p.Get("/pattern", func(w http.ResponseWriter, r *http.Request) {
+		pattern = RequestVars(r).Pattern()
+	})
+
+	hf := p.Serve()
+
+	r, _ := http.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		hf.ServeHTTP(w, r)
+	})
+	Equal(t, allocs, float64(0))
+
+	r, _ = http.NewRequest(http.MethodGet, "/pattern", nil)
+	hf.ServeHTTP(w, r)
+	Equal(t, pattern, "/pattern")
+}
+
 func TestBasePath(t *testing.T) {
 
 	p := New()
@@ -641,8 +942,8 @@ var githubAPI = []route{
 	// Gists
 	{"GET", "/users/:user/gists"},
 	{"GET", "/gists"},
-	//{"GET", "/gists/public"},
-	//{"GET", "/gists/starred"},
+	{"GET", "/gists/public"},
+	{"GET", "/gists/starred"},
 	{"GET", "/gists/:id"},
 	{"POST", "/gists"},
 	//{"PATCH", "/gists/:id"},
@@ -657,7 +958,7 @@ var githubAPI = []route{
 	{"POST", "/repos/:owner/:repo/git/blobs"},
 	{"GET", "/repos/:owner/:repo/git/commits/:sha"},
 	{"POST", "/repos/:owner/:repo/git/commits"},
-	//{"GET", "/repos/:owner/:repo/git/refs/*ref"},
+	{"GET", "/repos/:owner/:repo/git/refs/*ref"},
 	{"GET", "/repos/:owner/:repo/git/refs"},
 	{"POST", "/repos/:owner/:repo/git/refs"},
 	//{"PATCH", "/repos/:owner/:repo/git/refs/*ref"},
@@ -678,14 +979,14 @@ var githubAPI = []route{
 	{"GET", "/repos/:owner/:repo/assignees"},
 	{"GET", "/repos/:owner/:repo/assignees/:assignee"},
 	{"GET", "/repos/:owner/:repo/issues/:number/comments"},
-	//{"GET", "/repos/:owner/:repo/issues/comments"},
-	//{"GET", "/repos/:owner/:repo/issues/comments/:id"},
+	{"GET", "/repos/:owner/:repo/issues/comments"},
+	{"GET", "/repos/:owner/:repo/issues/comments/:id"},
 	{"POST", "/repos/:owner/:repo/issues/:number/comments"},
 	//{"PATCH", "/repos/:owner/:repo/issues/comments/:id"},
 	//{"DELETE", "/repos/:owner/:repo/issues/comments/:id"},
 	{"GET", "/repos/:owner/:repo/issues/:number/events"},
-	//{"GET", "/repos/:owner/:repo/issues/events"},
-	//{"GET", "/repos/:owner/:repo/issues/events/:id"},
+	{"GET", "/repos/:owner/:repo/issues/events"},
+	{"GET", "/repos/:owner/:repo/issues/events/:id"},
 	{"GET", "/repos/:owner/:repo/labels"},
 	{"GET", "/repos/:owner/:repo/labels/:name"},
 	{"POST", "/repos/:owner/:repo/labels"},
@@ -749,8 +1050,8 @@ var githubAPI = []route{
 	{"GET", "/repos/:owner/:repo/pulls/:number/merge"},
 	{"PUT", "/repos/:owner/:repo/pulls/:number/merge"},
 	{"GET", "/repos/:owner/:repo/pulls/:number/comments"},
-	//{"GET", "/repos/:owner/:repo/pulls/comments"},
-	//{"GET", "/repos/:owner/:repo/pulls/comments/:number"},
+	{"GET", "/repos/:owner/:repo/pulls/comments"},
+	{"GET", "/repos/:owner/:repo/pulls/comments/:number"},
 	{"PUT", "/repos/:owner/:repo/pulls/:number/comments"},
 	//{"PATCH", "/repos/:owner/:repo/pulls/comments/:number"},
 	//{"DELETE", "/repos/:owner/:repo/pulls/comments/:number"},
@@ -784,10 +1085,10 @@ var githubAPI = []route{
 	{"GET", "/repos/:owner/:repo/commits"},
 	{"GET", "/repos/:owner/:repo/commits/:sha"},
 	{"GET", "/repos/:owner/:repo/readme"},
-	//{"GET", "/repos/:owner/:repo/contents/*path"},
-	//{"PUT", "/repos/:owner/:repo/contents/*path"},
-	//{"DELETE", "/repos/:owner/:repo/contents/*path"},
-	//{"GET", "/repos/:owner/:repo/:archive_format/:ref"},
+	{"GET", "/repos/:owner/:repo/contents/*path"},
+	{"PUT", "/repos/:owner/:repo/contents/*path"},
+	{"DELETE", "/repos/:owner/:repo/contents/*path"},
+	{"GET", "/repos/:owner/:repo/:archive_format/:ref"},
 	{"GET", "/repos/:owner/:repo/keys"},
 	{"GET", "/repos/:owner/:repo/keys/:id"},
 	{"POST", "/repos/:owner/:repo/keys"},
@@ -914,3 +1215,134 @@ func requestMultiPart(method string, url string, p *Mux) (int, string) {
 
 	return wr.Code, wr.Body.String()
 }
+
+func TestCleanPath(t *testing.T) {
+
+	p := New()
+	p.SetCleanPath(true)
+	p.Get("/api/users/:id", idHandler)
+	p.Get("/home/", defaultHandler)
+	p.Post("/docs", defaultHandler)
+
+	hf := p.Serve()
+
+	tests := []struct {
+		method   string
+		url      string
+		code     int
+		location string
+	}{
+		{http.MethodGet, "/api/users/1", http.StatusOK, ""},
+		{http.MethodGet, "/api//users/../users/1", http.StatusMovedPermanently, "/api/users/1"},
+		{http.MethodGet, "/api/./users/1?page=2&sort=asc", http.StatusMovedPermanently, "/api/users/1?page=2&sort=asc"},
+		{http.MethodGet, "/x/../api/users/1", http.StatusMovedPermanently, "/api/users/1"},
+		{http.MethodGet, "/home//", http.StatusMovedPermanently, "/home/"},
+		{http.MethodPost, "/docs/./", http.StatusPermanentRedirect, "/docs"},
+
+		// cleaned path is then tried lowercase and with or without the trailing slash
+		{http.MethodGet, "/API//Users/1?q=a", http.StatusMovedPermanently, "/api/users/1?q=a"},
+		{http.MethodGet, "/home//../home", http.StatusMovedPermanently, "/home/"},
+		{http.MethodGet, "/Home/./", http.StatusMovedPermanently, "/home/"},
+		{http.MethodGet, "/api//users/1/", http.StatusMovedPermanently, "/api/users/1"},
+		{http.MethodPost, "/DOCS//?v=1", http.StatusPermanentRedirect, "/docs?v=1"},
+
+		{http.MethodGet, "/api//users", http.StatusNotFound, ""},
+		{http.MethodGet, "/../../etc/passwd", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.url, nil)
+		w := httptest.NewRecorder()
+		hf.ServeHTTP(w, r)
+		Equal(t, w.Code, tt.code)
+		Equal(t, w.Header().Get("Location"), tt.location)
+	}
+
+	// without redirecting trailing slashes only the cleaned path is tried
+	p.SetRedirectTrailingSlash(false)
+
+	tests = []struct {
+		method   string
+		url      string
+		code     int
+		location string
+	}{
+		{http.MethodGet, "/api//users/../users/1?q=a", http.StatusMovedPermanently, "/api/users/1?q=a"},
+		{http.MethodGet, "/home//", http.StatusMovedPermanently, "/home/"},
+		{http.MethodGet, "/API//Users/1", http.StatusNotFound, ""},
+		{http.MethodGet, "/home//../home", http.StatusNotFound, ""},
+		{http.MethodGet, "/api//users/1/", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.url, nil)
+		w := httptest.NewRecorder()
+		hf.ServeHTTP(w, r)
+		Equal(t, w.Code, tt.code)
+		Equal(t, w.Header().Get("Location"), tt.location)
+	}
+
+	// disabled by default
+	p = New()
+	p.Get("/api/users/:id", idHandler)
+
+	code, _ := request(http.MethodGet, "/api//users/../users/1", p)
+	Equal(t, code, http.StatusNotFound)
+}
+
+func TestUseRawPath(t *testing.T) {
+
+	paramsHandler := func(w http.ResponseWriter, r *http.Request) {
+		rv := RequestVars(r)
+		if _, err := w.Write([]byte(rv.URLParam("bucket") + "|" + rv.URLParam("key") + "|" + rv.URLParam("path"))); err != nil {
+			panic(err)
+		}
+	}
+
+	p := New()
+	p.UseRawPath(true)
+	p.Get("/buckets/:bucket/objects/:key", paramsHandler)
+	p.Get("/buckets/:bucket/objects/:key/acl", paramsHandler)
+	p.Get("/ids/:key<int>", paramsHandler)
+	p.Get("/files/*path", paramsHandler)
+	p.Get("/docs/a%20b", paramsHandler)
+	p.Get("/docs/:key", paramsHandler)
+
+	hf := p.Serve()
+
+	tests := []struct {
+		url      string
+		code     int
+		body     string
+		location string
+	}{
+		{"/buckets/b1/objects/a%2Fb%2Fc.txt", http.StatusOK, "b1|a/b/c.txt|", ""},
+		{"/buckets/b%201/objects/a%2Fb/acl", http.StatusOK, "b 1|a/b|", ""},
+		{"/buckets/b1/objects/plain", http.StatusOK, "b1|plain|", ""},
+		{"/buckets/b1/objects/a/b", http.StatusNotFound, "", ""},
+		{"/ids/%31%32", http.StatusOK, "|12|", ""},
+		{"/ids/%2F", http.StatusNotFound, "", ""},
+		{"/files/a%2Fb/c%20d", http.StatusOK, "||a/b/c d", ""},
+		{"/docs/a%20b", http.StatusOK, "||", ""},
+		{"/docs/a%2Fb", http.StatusOK, "|a/b|", ""},
+		{"/buckets/b1/objects/a%20b/?v=1", http.StatusMovedPermanently, "", "/buckets/b1/objects/a%20b?v=1"},
+	}
+
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+		w := httptest.NewRecorder()
+		hf.ServeHTTP(w, r)
+		Equal(t, w.Code, tt.code)
+		if tt.code == http.StatusOK {
+			Equal(t, w.Body.String(), tt.body)
+		}
+		Equal(t, w.Header().Get("Location"), tt.location)
+	}
+
+	// by default encoded slashes split the value into separate segments
+	p = New()
+	p.Get("/buckets/:bucket/objects/:key", paramsHandler)
+
+	code, _ := request(http.MethodGet, "/buckets/b1/objects/a%2Fb%2Fc.txt", p)
+	Equal(t, code, http.StatusNotFound)
+}
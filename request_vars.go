@@ -1,16 +1,27 @@
 package pure
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ReqVars is the interface of request scoped variables
 // tracked by pure
 type ReqVars interface {
 	URLParam(pname string) string
+	Pattern() string
+	AllowedMethods() []string
 }
 
+// requestVars is also the context.Context set on the request, layered on top of the
+// incoming requests context, so no additional allocations are required when storing it.
+// It's pooled so it must not be retained after the handler returns, once returned to the
+// pool it's parent is reset to context.Background().
 type requestVars struct {
-	ctx        context.Context // holds a copy of it's parent requestVars
+	parent     context.Context // the incoming requests context
 	params     urlParams
+	pattern    string
+	allowed    []string
 	formParsed bool
 }
 
@@ -18,3 +29,42 @@ type requestVars struct {
 func (r *requestVars) URLParam(pname string) string {
 	return r.params.Get(pname)
 }
+
+// Pattern returns the registered pattern of the matched route eg. /users/:id
+// or blank if no route was matched. Static routes are served without storing
+// the request vars, so outside of the route's handler and middleware eg. in pre
+// routing middleware, the requested path is returned when none are stored; which
+// is not the pattern if the path was since rewritten eg. by http.StripPrefix.
+func (r *requestVars) Pattern() string {
+	return r.pattern
+}
+
+// AllowedMethods returns the sorted methods allowed for the requested path, as set
+// in the Allow header, when handled by the automatic OPTIONS or 405 handlers, otherwise nil
+func (r *requestVars) AllowedMethods() []string {
+	return r.allowed
+}
+
+// Deadline returns the deadline of the incoming requests context
+func (r *requestVars) Deadline() (deadline time.Time, ok bool) {
+	return r.parent.Deadline()
+}
+
+// Done returns the done channel of the incoming requests context
+func (r *requestVars) Done() <-chan struct{} {
+	return r.parent.Done()
+}
+
+// Err returns the error of the incoming requests context
+func (r *requestVars) Err() error {
+	return r.parent.Err()
+}
+
+// Value returns the requestVars for pure's context key, otherwise
+// defers to the incoming requests context
+func (r *requestVars) Value(key interface{}) interface{} {
+	if key == defaultContextIdentifier {
+		return r
+	}
+	return r.parent.Value(key)
+}
@@ -0,0 +1,142 @@
+package pure
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Route is returned when registering a route and allows for
+// further configuration of the registered route, such as naming it.
+type Route struct {
+	pattern string
+	infos   []*RouteInfo
+	pure    *Mux
+}
+
+// RouteInfo contains the information about a single registered route
+type RouteInfo struct {
+	// Method is the HTTP method the route is registered for
+	Method string
+
+	// Host is the host pattern the route is registered for, blank for any host
+	Host string
+
+	// Pattern is the full pattern, including any group prefix
+	Pattern string
+
+	// Name is the name given to the route, if any
+	Name string
+
+	// Handler is the function name of the routes handler
+	Handler string
+
+	// Middleware is the number of middleware wrapping the handler
+	Middleware int
+
+	// Metadata is the information set using WithMetadata, if any
+	Metadata map[string]interface{}
+
+	// Disabled is the status code the route responds with while disabled using Mux.Disable, 0 when enabled
+	Disabled int
+}
+
+// Pattern returns the full registered pattern of the route, including any group prefix.
+func (r *Route) Pattern() string {
+	return r.pattern
+}
+
+// Name registers the route under the given name so that it's URL
+// can be built using Mux.URL
+func (r *Route) Name(name string) *Route {
+	if existing, ok := r.pure.table.names[name]; ok && existing.pattern != r.pattern {
+		panic(&DuplicateNameError{Name: name, Path: existing.pattern})
+	}
+	r.pure.table.names[name] = r
+	for _, info := range r.infos {
+		info.Name = name
+	}
+	return r
+}
+
+// Routes returns the information of every route being served, in registration order.
+// Routes registered for multiple methods eg. using Any or Match are returned once per method.
+// It's safe to call while the routes are updated.
+func (p *Mux) Routes() []RouteInfo {
+	t := p.served()
+	routes := make([]RouteInfo, len(t.routes))
+	for i, info := range t.routes {
+		routes[i] = *info
+	}
+	return routes
+}
+
+// URL builds the URL for the route registered under the given name,
+// substituting the param values provided as key value pairs
+// eg. p.URL("user.posts", "id", "42", pure.WildcardParam, "a/b")
+//
+// Param values are escaped, the catch-all value retains it's '/' separators. Only the
+// routes being served are used, so it's safe to call while the routes are updated.
+func (p *Mux) URL(name string, params ...string) (string, error) {
+	r, ok := p.served().names[name]
+	if !ok {
+		return blank, errors.New("no route registered with name '" + name + "'")
+	}
+	if len(params)%2 != 0 {
+		return blank, errors.New("odd number of param key value pairs provided for route '" + name + "'")
+	}
+	return buildURL(r.pattern, params)
+}
+
+// buildURL substitutes the params, in key value pairs, into the route pattern
+func buildURL(pattern string, params []string) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(pattern))
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != paramByte && c != wildByte {
+			sb.WriteByte(c)
+			continue
+		}
+
+		end := wildcardEnd(pattern, i)
+		key, _ := splitParam(pattern[i+1 : end])
+		if c == wildByte {
+			key = catchAllKey(key)
+		}
+
+		value, ok := lookupParam(params, key)
+		if !ok {
+			return blank, errors.New("missing value for param '" + key + "' in path '" + pattern + "'")
+		}
+
+		if c == wildByte {
+			sb.WriteString((&url.URL{Path: value}).EscapedPath())
+		} else {
+			sb.WriteString(url.PathEscape(value))
+		}
+		i = end - 1
+	}
+	return sb.String(), nil
+}
+
+func lookupParam(params []string, key string) (string, bool) {
+	for i := 0; i < len(params)-1; i += 2 {
+		if params[i] == key {
+			return params[i+1], true
+		}
+	}
+	return blank, false
+}
+
+// handlerName returns the function name of the provided handler
+// eg. an http.HandlerFunc or HandlerFuncE
+func handlerName(h interface{}) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return blank
+}
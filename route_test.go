@@ -0,0 +1,149 @@
+package pure
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestNamedRoutes(t *testing.T) {
+
+	p := New()
+	p.Get("/", defaultHandler).Name("home")
+	p.Get("/users/:id/posts/*wildcard", defaultHandler).Name("user.posts")
+
+	g := p.Group("/admin/:org")
+	g.Get("/users/:id", defaultHandler).Name("admin.user")
+	g.Any("/any", defaultHandler).Name("admin.any")
+	p.Match([]string{http.MethodGet, http.MethodPost}, "/match", defaultHandler).Name("match")
+
+	u, err := p.URL("home")
+	Equal(t, err, nil)
+	Equal(t, u, "/")
+
+	u, err = p.URL("user.posts", "id", "42", WildcardParam, "a/b")
+	Equal(t, err, nil)
+	Equal(t, u, "/users/42/posts/a/b")
+
+	u, err = p.URL("user.posts", "id", "a/b c", WildcardParam, "x y/z?")
+	Equal(t, err, nil)
+	Equal(t, u, "/users/a%2Fb%20c/posts/x%20y/z%3F")
+
+	u, err = p.URL("admin.user", "org", "acme", "id", "7")
+	Equal(t, err, nil)
+	Equal(t, u, "/admin/acme/users/7")
+
+	u, err = p.URL("admin.any", "org", "acme")
+	Equal(t, err, nil)
+	Equal(t, u, "/admin/acme/any")
+
+	u, err = p.URL("match")
+	Equal(t, err, nil)
+	Equal(t, u, "/match")
+
+	_, err = p.URL("admin.user", "org", "acme")
+	Equal(t, err.Error(), "missing value for param 'id' in path '/admin/:org/users/:id'")
+
+	_, err = p.URL("user.posts", "id", "42")
+	Equal(t, err.Error(), "missing value for param '*wildcard' in path '/users/:id/posts/*wildcard'")
+
+	_, err = p.URL("admin.user", "org")
+	Equal(t, err.Error(), "odd number of param key value pairs provided for route 'admin.user'")
+
+	_, err = p.URL("unknown")
+	Equal(t, err.Error(), "no route registered with name 'unknown'")
+
+	r := p.Post("/users/:id/posts/*wildcard", defaultHandler)
+	Equal(t, r.Pattern(), "/users/:id/posts/*wildcard")
+	r.Name("user.posts")
+
+	PanicMatches(t, func() { p.Get("/other", defaultHandler).Name("home") }, "Route name 'home' is already registered for path '/'")
+}
+
+func namedHandler(w http.ResponseWriter, r *http.Request) {}
+
+func TestRoutes(t *testing.T) {
+
+	p := New()
+	p.Use(defaultMiddleware)
+	p.Get("/users/:id", namedHandler).Name("user")
+	p.Handle("PROPFIND", "/dav", defaultHandler)
+
+	g := p.GroupWithMore("/admin", defaultMiddleware)
+	g.Any("/any", defaultHandler)
+	p.Match([]string{http.MethodGet, http.MethodPost}, "/match", defaultHandler)
+
+	routes := p.Routes()
+	Equal(t, len(routes), 13)
+
+	Equal(t, routes[0].Method, http.MethodGet)
+	Equal(t, routes[0].Pattern, "/users/:id")
+	Equal(t, routes[0].Name, "user")
+	Equal(t, routes[0].Handler, "github.com/go-playground/pure/v5.namedHandler")
+	Equal(t, routes[0].Middleware, 1)
+
+	Equal(t, routes[1].Method, "PROPFIND")
+	Equal(t, routes[1].Pattern, "/dav")
+	Equal(t, routes[1].Name, "")
+
+	for i, m := range anyMethods {
+		Equal(t, routes[i+2].Method, m)
+		Equal(t, routes[i+2].Pattern, "/admin/any")
+		Equal(t, routes[i+2].Middleware, 2)
+	}
+
+	Equal(t, routes[11].Method, http.MethodGet)
+	Equal(t, routes[11].Pattern, "/match")
+	Equal(t, routes[12].Method, http.MethodPost)
+	Equal(t, routes[12].Pattern, "/match")
+
+	// returned values are copies
+	routes[0].Pattern = "/changed"
+	Equal(t, p.Routes()[0].Pattern, "/users/:id")
+}
+
+func TestRoutesDuringUpdate(t *testing.T) {
+
+	p := New()
+	p.Get("/users/:id", defaultHandler).Name("user")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			p.Update(func(g IRouteGroup) {
+				g.Get("/route"+strconv.Itoa(i), defaultHandler).Name("route" + strconv.Itoa(i))
+			})
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			Equal(t, len(p.Routes()), 101)
+			u, err := p.URL("route99")
+			Equal(t, err, nil)
+			Equal(t, u, "/route99")
+			return
+		default:
+		}
+
+		u, err := p.URL("user", "id", "13")
+		Equal(t, err, nil)
+		Equal(t, u, "/users/13")
+		NotEqual(t, len(p.Routes()), 0)
+	}
+}
@@ -0,0 +1,209 @@
+package pure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Rule is a URL rewrite or redirect rule, as loaded by LoadRules eg.
+//
+//	[
+//		{"from": "/old/:id", "to": "/new/:id"},
+//		{"from": "/blog/*wildcard", "to": "https://blog.example.com/*wildcard", "code": 301}
+//	]
+type Rule struct {
+	// From is the pattern matched against the request path, using the same syntax as routes
+	From string `json:"from"`
+
+	// To is the path, or URL when redirecting, the params matched by From are substituted into
+	To string `json:"to"`
+
+	// Code is the redirect status code, 0 to rewrite the request internally
+	Code int `json:"code,omitempty"`
+}
+
+// ruleMatched is the handler stored on the rules tree, the matched
+// rule is looked up using the matched pattern
+var ruleMatched http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {}
+
+// Rewrite internally rewrites requests, for any method, whose path matches the from pattern
+// to the to path, substituting the matched params eg. p.Rewrite("/old/:id", "/new/:id"). The
+// route is then matched against the rewritten path, rules are not applied again. The query
+// string is preserved unless to contains one.
+//
+// Rules are applied before the routes are matched, however after pre-routing middleware.
+// Once serving, rules must only be added within Update, adding them otherwise is a data race.
+func (p *Mux) Rewrite(from string, to string) {
+	if err := p.addRules(Rule{From: from, To: to}); err != nil {
+		panic(err)
+	}
+}
+
+// Redirect redirects requests, for any method, whose path matches the from pattern to the to
+// path or URL, substituting the matched params, with the provided status code
+// eg. p.Redirect("/blog/*wildcard", "https://blog.example.com/*wildcard", http.StatusMovedPermanently).
+// The query string is preserved unless to contains one. The code must be a 3xx redirect status.
+//
+// Once serving, rules must only be added within Update, adding them otherwise is a data race.
+func (p *Mux) Redirect(from string, to string, code int) {
+	if code < 300 || code > 399 {
+		panic(&InvalidPathError{Path: from, Reason: "invalid redirect code " + strconv.Itoa(code) + " for rule"})
+	}
+	if err := p.addRules(Rule{From: from, To: to, Code: code}); err != nil {
+		panic(err)
+	}
+}
+
+// LoadRules loads the rewrite and redirect rules from the JSON file containing an array of
+// Rule's. An error is returned if the file can't be read or any rule is invalid, in which
+// case none of the rules are added. Once serving, rules must only be loaded within Update,
+// loading them otherwise is a data race.
+func (p *Mux) LoadRules(filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var rules []Rule
+	if err = json.Unmarshal(b, &rules); err != nil {
+		return err
+	}
+	return p.addRules(rules...)
+}
+
+// addRules adds the rules, leaving the existing rules unchanged if any are invalid
+func (p *Mux) addRules(rules ...Rule) error {
+	t := p.table
+
+	tree := t.rules
+	if tree == nil {
+		tree = new(node)
+	}
+
+	added := make(map[string]Rule, len(rules))
+
+	for _, rule := range rules {
+		if rule.Code != 0 && (rule.Code < 300 || rule.Code > 399) {
+			return &InvalidPathError{Path: rule.From, Reason: "invalid redirect code " + strconv.Itoa(rule.Code) + " for rule"}
+		}
+		if rule.To == blank {
+			return &InvalidPathError{Path: rule.From, Reason: "blank rule target"}
+		}
+		if i := strings.Index(rule.From, "//"); i != -1 {
+			return &DoubleSlashError{Path: rule.From, Index: i}
+		}
+
+		var err error
+		if tree, _, err = tree.add(rule.From, ruleMatched, p); err != nil {
+			return err
+		}
+		added[rule.From] = rule
+	}
+
+	for from, rule := range added {
+		t.ruleTargets[from] = rule
+	}
+	t.rules = tree
+	return nil
+}
+
+// applyRules applies the rule matching the path, if any, returning the redirect handler
+// or the rewritten request; both nil when no rule matches.
+func (p *Mux) applyRules(t *table, r *http.Request, path string) (http.HandlerFunc, *http.Request) {
+	leaf, rv := t.rules.find(path, nil, p)
+	var params urlParams
+	if rv != nil {
+		params = rv.params
+		defer p.pool.Put(rv)
+	}
+
+	if leaf == nil {
+		return nil, nil
+	}
+
+	rule := t.ruleTargets[leaf.pattern]
+	to := expandTarget(rule.To, params)
+
+	if rule.Code != 0 {
+		if r.URL.RawQuery != blank && strings.IndexByte(to, '?') == -1 {
+			to += "?" + r.URL.RawQuery
+		}
+		code := rule.Code
+		var h http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, to, code)
+		}
+		for i := len(p.middleware) - 1; i >= 0; i-- {
+			h = p.middleware[i](h)
+		}
+		return h, nil
+	}
+
+	u, err := url.Parse(to)
+	if err != nil {
+		return nil, nil
+	}
+
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = u.Path
+	r2.URL.RawPath = u.RawPath
+	if u.RawQuery != blank {
+		r2.URL.RawQuery = u.RawQuery
+	}
+	return nil, r2
+}
+
+// expandTarget substitutes the matched params into the rule's target, escaping their
+// values, the catch-all value retaining it's '/' separators. Wildcards not matched by
+// the rule's pattern are left as is eg. the port of a URL.
+func expandTarget(target string, params urlParams) string {
+	var sb strings.Builder
+	sb.Grow(len(target))
+
+	for i := 0; i < len(target); i++ {
+		c := target[i]
+		if c != paramByte && c != wildByte {
+			sb.WriteByte(c)
+			continue
+		}
+
+		end := i + 1
+		for end < len(target) && isNameByte(target[end]) {
+			end++
+		}
+
+		key := target[i+1 : end]
+		if c == wildByte {
+			key = catchAllKey(key)
+		}
+
+		value, ok := lookupURLParam(params, key)
+		if !ok {
+			sb.WriteByte(c)
+			continue
+		}
+
+		if c == wildByte {
+			sb.WriteString((&url.URL{Path: value}).EscapedPath())
+		} else {
+			sb.WriteString(url.PathEscape(value))
+		}
+		i = end - 1
+	}
+	return sb.String()
+}
+
+func lookupURLParam(params urlParams, key string) (string, bool) {
+	for _, p := range params {
+		if p.key == key {
+			return p.value, true
+		}
+	}
+	return blank, false
+}
@@ -0,0 +1,127 @@
+package pure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestRules(t *testing.T) {
+	p := New()
+	p.Get("/new/:id", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(RequestVars(r).Pattern() + " " + RequestVars(r).URLParam("id") + " " + r.URL.RawQuery))
+	})
+	p.Get("/files/*", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(RequestVars(r).URLParam(WildcardParam)))
+	})
+	p.Get("/legacy", defaultHandler)
+
+	p.Rewrite("/old/:id", "/new/:id")
+	p.Rewrite("/docs/*path", "/files/*path?from=docs")
+	p.Rewrite("/loop", "/legacy")
+	p.Rewrite("/legacy", "/loop")
+	p.Redirect("/blog/*wildcard", "https://blog.example.com:8443/*wildcard", http.StatusMovedPermanently)
+	p.Redirect("/users/:id/profile", "/profiles/:id", http.StatusFound)
+
+	tests := []struct {
+		method   string
+		path     string
+		code     int
+		body     string
+		location string
+	}{
+		{http.MethodGet, "/old/13?page=2", http.StatusOK, "/new/:id 13 page=2", ""},
+		{http.MethodGet, "/old/a%20b", http.StatusOK, "/new/:id a b ", ""},
+		{http.MethodGet, "/docs/a/b c.txt", http.StatusOK, "a/b c.txt", ""},
+		{http.MethodPost, "/old/13", http.StatusNotFound, "Not Found\n", ""},
+		// rules aren't applied again once rewritten
+		{http.MethodGet, "/loop", http.StatusOK, http.MethodGet, ""},
+		{http.MethodGet, "/blog/2020/hello world?ref=1", http.StatusMovedPermanently, "", "https://blog.example.com:8443/2020/hello%20world?ref=1"},
+		{http.MethodPost, "/users/1/profile", http.StatusFound, "", "/profiles/1"},
+		{http.MethodGet, "/users/1/other", http.StatusNotFound, "Not Found\n", ""},
+	}
+
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		p.serveHTTP(w, r)
+		Equal(t, w.Code, tt.code)
+		if tt.location != "" {
+			Equal(t, w.Header().Get("Location"), tt.location)
+		} else {
+			Equal(t, w.Body.String(), tt.body)
+		}
+	}
+
+	PanicMatches(t, func() { p.Rewrite("/old/:id", "/other/:id") }, "handlers are already registered for path '/old/:id'")
+	PanicMatches(t, func() { p.Redirect("/moved", "/here", http.StatusOK) }, "invalid redirect code 200 for rule in path '/moved'")
+	PanicMatches(t, func() { p.Redirect("/moved", "/here", 0) }, "invalid redirect code 0 for rule in path '/moved'")
+	PanicMatches(t, func() { p.Rewrite("/empty", "") }, "blank rule target in path '/empty'")
+
+	// the escaped param values are retained when using the raw path
+	p = New()
+	p.UseRawPath(true)
+	p.Get("/new/:id", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(RequestVars(r).URLParam("id")))
+	})
+	p.Rewrite("/old/:id", "/new/:id")
+
+	code, body := request(http.MethodGet, "/old/a%2Fb", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "a/b")
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "rules.json")
+	err := os.WriteFile(valid, []byte(`[
+		{"from": "/old/:id", "to": "/new/:id"},
+		{"from": "/blog/*wildcard", "to": "https://blog.example.com/*wildcard", "code": 308}
+	]`), 0600)
+	Equal(t, err, nil)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	err = os.WriteFile(invalid, []byte(`[
+		{"from": "/other", "to": "/new/1"},
+		{"from": "/old/:name", "to": "/new/:name"}
+	]`), 0600)
+	Equal(t, err, nil)
+
+	p := New()
+	p.Get("/new/:id", defaultHandler)
+	p.Get("/new/1", defaultHandler)
+
+	Equal(t, p.LoadRules(valid), nil)
+	NotEqual(t, p.LoadRules(invalid), nil)
+	NotEqual(t, p.LoadRules(filepath.Join(dir, "missing.json")), nil)
+
+	code, body := request(http.MethodGet, "/old/1", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, http.MethodGet)
+
+	r, _ := http.NewRequest(http.MethodPost, "/blog/a", nil)
+	w := httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusPermanentRedirect)
+	Equal(t, w.Header().Get("Location"), "https://blog.example.com/a")
+
+	// none of the invalid file's rules were added
+	code, _ = request(http.MethodGet, "/other", p)
+	Equal(t, code, http.StatusNotFound)
+}
@@ -0,0 +1,168 @@
+package pure
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// schema is a JSON Schema, as used by OpenAPI 3.1, describing a Go type
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemas reflects the schemas of Go types, named struct types being
+// added to the components and referenced
+type schemas struct {
+	components map[string]*schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// of returns the schema of the type, as encoded using encoding/json
+func (s *schemas) of(t reflect.Type) *schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &schema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return &schema{}
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return &schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0
+		return &schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32:
+		return &schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == blank {
+			return s.object(t)
+		}
+		return s.ref(t)
+	}
+	// interfaces, and any other kind, may hold any value
+	return &schema{}
+}
+
+// ref adds the named struct type to the components, if not already, and returns a reference to it
+func (s *schemas) ref(t reflect.Type) *schema {
+	name, ok := s.names[t]
+	if !ok {
+		name = schemaName(t.Name())
+		if _, taken := s.components[name]; taken {
+			name = schemaName(t.PkgPath() + "." + t.Name())
+		}
+		s.names[t] = name
+
+		// registered before reflecting the fields so recursive types reference it
+		s.components[name] = nil
+		s.components[name] = s.object(t)
+	}
+	return &schema{Ref: "#/components/schemas/" + name}
+}
+
+// object returns the schema of the struct, embedded structs' fields being promoted
+func (s *schemas) object(t reflect.Type) *schema {
+	o := &schema{
+		Type:       "object",
+		Properties: make(map[string]*schema),
+	}
+	s.fields(t, o)
+	return o
+}
+
+func (s *schemas) fields(t reflect.Type, o *schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name, opts := f.Name, blank
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			name, opts = tag, blank
+			if j := strings.IndexByte(tag, ','); j != -1 {
+				name, opts = tag[:j], tag[j:]
+			}
+			if name == blank {
+				name = f.Name
+			}
+		}
+
+		ft := f.Type
+		if f.Anonymous && f.Tag.Get("json") == blank {
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.fields(ft, o)
+				continue
+			}
+		}
+
+		if f.PkgPath != blank { // unexported
+			continue
+		}
+
+		o.Properties[name] = s.of(f.Type)
+		if f.Type.Kind() != reflect.Ptr && !strings.Contains(opts, ",omitempty") {
+			o.Required = append(o.Required, name)
+		}
+	}
+}
+
+// schemaName returns the name, with any characters not permitted in a component name
+// replaced, eg. the brackets of generic types
+func schemaName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || (r >= '0' && r <= '9') || (r|0x20 >= 'a' && r|0x20 <= 'z') {
+			return r
+		}
+		return '_'
+	}, strings.ReplaceAll(name, "/", "."))
+}
@@ -0,0 +1,79 @@
+package pure
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+type schemaBase struct {
+	ID      uint64    `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type schemaNode struct {
+	schemaBase
+	Name     string            `json:"name,omitempty"`
+	Score    float64           `json:"score"`
+	Parent   *schemaNode       `json:"parent"`
+	Children []schemaNode      `json:"children"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Data     []byte            `json:"data,omitempty"`
+	IP       net.IP            `json:"ip,omitempty"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	Extra    interface{}       `json:"extra,omitempty"`
+	Inline   struct{ On bool } `json:"inline"`
+	Ignored  string            `json:"-"`
+	Untagged int16
+	hidden   string
+}
+
+type schemaPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestSchemas(t *testing.T) {
+	s := newSchemas()
+
+	ref := s.of(reflect.TypeOf(&schemaNode{}))
+	Equal(t, *ref, schema{Ref: "#/components/schemas/schemaNode"})
+
+	zero := 0
+	node := s.components["schemaNode"]
+	Equal(t, node.Type, "object")
+	Equal(t, node.Required, []string{"id", "created", "score", "children", "inline", "Untagged"})
+	Equal(t, len(node.Properties), 13)
+	Equal(t, *node.Properties["id"], schema{Type: "integer", Minimum: &zero})
+	Equal(t, *node.Properties["created"], schema{Type: "string", Format: "date-time"})
+	Equal(t, *node.Properties["score"], schema{Type: "number", Format: "double"})
+	Equal(t, *node.Properties["parent"], schema{Ref: "#/components/schemas/schemaNode"})
+	Equal(t, *node.Properties["children"], schema{Type: "array", Items: &schema{Ref: "#/components/schemas/schemaNode"}})
+	Equal(t, *node.Properties["labels"], schema{Type: "object", AdditionalProperties: &schema{Type: "string"}})
+	Equal(t, *node.Properties["data"], schema{Type: "string", Format: "byte"})
+	Equal(t, *node.Properties["ip"], schema{Type: "string"})
+	Equal(t, *node.Properties["raw"], schema{})
+	Equal(t, *node.Properties["extra"], schema{})
+	Equal(t, *node.Properties["inline"], schema{Type: "object", Properties: map[string]*schema{"On": {Type: "boolean"}}, Required: []string{"On"}})
+	Equal(t, *node.Properties["Untagged"], schema{Type: "integer", Format: "int32"})
+
+	ref = s.of(reflect.TypeOf(schemaPage[schemaNode]{}))
+	Equal(t, ref.Ref, "#/components/schemas/schemaPage_github.com.go-playground.pure.v5.schemaNode_")
+	Equal(t, len(s.components), 2)
+
+	Equal(t, *s.of(reflect.TypeOf([]int64{})), schema{Type: "array", Items: &schema{Type: "integer", Format: "int64"}})
+}
@@ -0,0 +1,367 @@
+package pure
+
+import "net/http"
+
+// table holds the registered routes. Once serving, the table being served is never
+// modified, instead routes are registered on a copy which then replaces it.
+//
+// The trees are never modified in place either, adding a route copies the nodes along
+// it's path, so copying a table only requires copying the maps.
+type table struct {
+	trees map[string]*node
+
+	// hosts holds the route trees of each host registered using Host, by host pattern
+	hosts map[string]map[string]*node
+
+	// hostTree matches the request host to the registered host patterns,
+	// nil when no hosts are registered
+	hostTree *node
+
+	// methods holds the sorted methods having a route tree, by host pattern,
+	// blank for the routes registered directly on the Mux, used to build the
+	// Allow header in a deterministic order
+	methods map[string]*allowedMethods
+
+	// allowed holds the sorted methods having a route registered for each
+	// route pattern, by host pattern, used to build the Allow header
+	allowed map[string]map[string]*allowedMethods
+
+	// notFound and methodNotAllowed hold the 404 and 405 handlers registered by groups,
+	// matching their prefix and every path beneath it, by host pattern
+	notFound         map[string]*node
+	methodNotAllowed map[string]*node
+
+	// mounts holds the handlers mounted using Mount, matching their prefix and every
+	// path beneath it, by host pattern; used for the methods not registered by Any
+	mounts map[string]*node
+
+	// rules matches the request path to the patterns of the rewrite and redirect
+	// rules, held by pattern in ruleTargets; nil when no rules are added
+	rules       *node
+	ruleTargets map[string]Rule
+
+	// names holds the named routes used to build URLs
+	names map[string]*Route
+
+	// routes holds the information of every registered route in registration order
+	routes []*RouteInfo
+
+	// mostParams used to keep track of the most amount of
+	// params in any URL and this will set the default capacity
+	// of each Params
+	mostParams uint8
+}
+
+func newTable() *table {
+	return &table{
+		trees:   make(map[string]*node),
+		hosts:   make(map[string]map[string]*node),
+		methods: make(map[string]*allowedMethods),
+		allowed: make(map[string]map[string]*allowedMethods),
+		names:   make(map[string]*Route),
+
+		notFound:         make(map[string]*node),
+		methodNotAllowed: make(map[string]*node),
+		mounts:           make(map[string]*node),
+		ruleTargets:      make(map[string]Rule),
+	}
+}
+
+// copy returns a copy of the table that can be modified without affecting the original
+func (t *table) copy() *table {
+	c := &table{
+		trees:    make(map[string]*node, len(t.trees)),
+		hosts:    make(map[string]map[string]*node, len(t.hosts)),
+		hostTree: t.hostTree,
+		methods:  make(map[string]*allowedMethods, len(t.methods)),
+		allowed:  make(map[string]map[string]*allowedMethods, len(t.allowed)),
+
+		notFound:         make(map[string]*node, len(t.notFound)),
+		methodNotAllowed: make(map[string]*node, len(t.methodNotAllowed)),
+		mounts:           make(map[string]*node, len(t.mounts)),
+		rules:            t.rules,
+		ruleTargets:      make(map[string]Rule, len(t.ruleTargets)),
+		names:            make(map[string]*Route, len(t.names)),
+		routes:           make([]*RouteInfo, len(t.routes)),
+		mostParams:       t.mostParams,
+	}
+	for m, tree := range t.trees {
+		c.trees[m] = tree
+	}
+	for h, trees := range t.hosts {
+		ht := make(map[string]*node, len(trees))
+		for m, tree := range trees {
+			ht[m] = tree
+		}
+		c.hosts[h] = ht
+	}
+	for h, methods := range t.methods {
+		c.methods[h] = methods
+	}
+	for h, patterns := range t.allowed {
+		ap := make(map[string]*allowedMethods, len(patterns))
+		for pattern, methods := range patterns {
+			ap[pattern] = methods
+		}
+		c.allowed[h] = ap
+	}
+	for h, tree := range t.notFound {
+		c.notFound[h] = tree
+	}
+	for h, tree := range t.methodNotAllowed {
+		c.methodNotAllowed[h] = tree
+	}
+	for h, tree := range t.mounts {
+		c.mounts[h] = tree
+	}
+	for from, rule := range t.ruleTargets {
+		c.ruleTargets[from] = rule
+	}
+	for name, r := range t.names {
+		c.names[name] = r
+	}
+	copy(c.routes, t.routes)
+	return c
+}
+
+// served returns the table currently being served
+func (p *Mux) served() *table {
+	return p.live.Load().(*table)
+}
+
+// Update atomically updates the routes of a running server. The routes registered by fn,
+// using the provided group or any other, are added to a copy of the current routes which
+// replaces them once fn returns; in-flight requests are not blocked and continue to use the
+// routes they were matched against. If fn panics the current routes are left unchanged.
+//
+// Concurrent calls to Update are serialized. Once serving, routes must only be registered
+// within Update.
+func (p *Mux) Update(fn func(g IRouteGroup)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current := p.table
+	p.table = current.copy()
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.table = current
+			panic(r)
+		}
+	}()
+
+	fn(p.Group(blank))
+	p.live.Store(p.table)
+}
+
+// Swap atomically replaces the routes of a running server with those registered on
+// the provided Mux, which must not be used afterwards. The routes keep the middleware
+// they were registered with, as do the 404 and 405 handlers registered by groups, however
+// the Mux's 404, 405 and OPTIONS handling remains that of p.
+func (p *Mux) Swap(with *Mux) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	with.mu.Lock()
+	t := with.table
+	with.mu.Unlock()
+
+	p.table = t
+	p.live.Store(t)
+}
+
+// Remove removes the route registered directly on the Mux, or any of it's groups, for the
+// method and pattern, as registered eg. /users/:id, returning false if no such route is
+// registered. It's safe to call while serving, but not from within Update.
+func (p *Mux) Remove(method string, pattern string) bool {
+	return p.RemoveHost(blank, method, pattern)
+}
+
+// RemoveHost is the same as Remove for the route registered using Host for the host pattern,
+// as registered eg. :tenant.example.com
+func (p *Mux) RemoveHost(host string, method string, pattern string) (removed bool) {
+	host = hostPattern(host)
+
+	p.Update(func(IRouteGroup) {
+		var registered string
+		removed = p.table.modify(host, method, pattern, func(leaf *node) {
+			registered = leaf.pattern
+			leaf.handler = nil
+			leaf.disabled = nil
+			leaf.pattern = blank
+		}, p)
+		if removed {
+			p.table.removeInfo(host, method, pattern)
+			p.table.disallow(host, registered, method)
+		}
+	})
+	return
+}
+
+// Disable takes the route registered directly on the Mux, or any of it's groups, for the
+// method and pattern out of service, responding with the provided status code instead
+// eg. http.StatusGone or http.StatusServiceUnavailable, until enabled again using Enable.
+// false is returned if no such route is registered. It's safe to call while serving, but
+// not from within Update.
+func (p *Mux) Disable(method string, pattern string, status int) bool {
+	return p.DisableHost(blank, method, pattern, status)
+}
+
+// DisableHost is the same as Disable for the route registered using Host for the host
+// pattern, as registered eg. :tenant.example.com
+func (p *Mux) DisableHost(host string, method string, pattern string, status int) (disabled bool) {
+	host = hostPattern(host)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(status), status)
+	}
+
+	for i := len(p.middleware) - 1; i >= 0; i-- {
+		h = p.middleware[i](h)
+	}
+
+	p.Update(func(IRouteGroup) {
+		disabled = p.table.modify(host, method, pattern, func(leaf *node) {
+			leaf.disabled = h
+		}, p)
+		if disabled {
+			p.table.setDisabled(host, method, pattern, status)
+		}
+	})
+	return
+}
+
+// Enable puts a route taken out of service using Disable back into service, returning
+// false if no such route is registered. It's safe to call while serving, but not from
+// within Update.
+func (p *Mux) Enable(method string, pattern string) bool {
+	return p.EnableHost(blank, method, pattern)
+}
+
+// EnableHost is the same as Enable for the route registered using Host for the host
+// pattern, as registered eg. :tenant.example.com
+func (p *Mux) EnableHost(host string, method string, pattern string) (enabled bool) {
+	host = hostPattern(host)
+
+	p.Update(func(IRouteGroup) {
+		enabled = p.table.modify(host, method, pattern, func(leaf *node) {
+			leaf.disabled = nil
+		}, p)
+		if enabled {
+			p.table.setDisabled(host, method, pattern, 0)
+		}
+	})
+	return
+}
+
+// modify modifies the node having the handler registered for the host pattern, method and
+// route pattern, see node.modify, returning false if no such route is registered.
+func (t *table) modify(host string, method string, pattern string, fn func(leaf *node), mux *Mux) bool {
+	tree := t.routeTrees(host)[method]
+	if tree == nil {
+		return false
+	}
+
+	path, err := mux.routePath(pattern)
+	if err != nil {
+		return false
+	}
+
+	tree, ok := tree.modify(path, fn)
+	if !ok {
+		return false
+	}
+
+	if tree.priority == 0 {
+		tree = nil
+	}
+	t.setTree(host, method, tree)
+	return true
+}
+
+// routeTrees returns the route trees of the host pattern, blank for
+// the routes registered directly on the Mux
+func (t *table) routeTrees(host string) map[string]*node {
+	if host == blank {
+		return t.trees
+	}
+	return t.hosts[host]
+}
+
+// setTree sets the route tree of the method for the host pattern, removing
+// it when nil, and keeps the sorted methods in sync. The methods are never
+// modified in place as they're shared with copies of the table.
+func (t *table) setTree(host string, method string, tree *node) {
+	trees := t.routeTrees(host)
+	_, exists := trees[method]
+
+	if tree == nil {
+		if exists {
+			delete(trees, method)
+			t.methods[host] = newAllowedMethods(removeMethod(t.methods[host].list(), method))
+		}
+		return
+	}
+
+	trees[method] = tree
+	if !exists {
+		t.methods[host] = newAllowedMethods(insertMethod(t.methods[host].list(), method))
+	}
+}
+
+// allow adds the method to those having a route registered for the route pattern of the host
+// pattern. The allowed methods are never modified in place as they're shared with copies of the table.
+func (t *table) allow(host string, pattern string, method string) {
+	patterns := t.allowed[host]
+	if patterns == nil {
+		patterns = make(map[string]*allowedMethods)
+		t.allowed[host] = patterns
+	}
+	patterns[pattern] = newAllowedMethods(insertMethod(patterns[pattern].list(), method))
+}
+
+// disallow removes the method from those having a route registered for the route pattern of
+// the host pattern, once the route is removed.
+func (t *table) disallow(host string, pattern string, method string) {
+	patterns := t.allowed[host]
+	if methods := removeMethod(patterns[pattern].list(), method); len(methods) > 0 {
+		patterns[pattern] = newAllowedMethods(methods)
+		return
+	}
+	delete(patterns, pattern)
+}
+
+// removeInfo removes the information of the removed route along with
+// it's name once no longer used by any other route
+func (t *table) removeInfo(host string, method string, pattern string) {
+	for i, info := range t.routes {
+		if info.Method != method || info.Host != host || info.Pattern != pattern {
+			continue
+		}
+		t.routes = append(t.routes[:i], t.routes[i+1:]...)
+
+		if info.Name == blank {
+			return
+		}
+		for _, other := range t.routes {
+			if other.Name == info.Name {
+				return
+			}
+		}
+		delete(t.names, info.Name)
+		return
+	}
+}
+
+// setDisabled sets the status code the route responds with while disabled, 0 when enabled,
+// on the route's information; which is copied as it's shared with the table being served.
+func (t *table) setDisabled(host string, method string, pattern string, status int) {
+	for i, info := range t.routes {
+		if info.Method == method && info.Host == host && info.Pattern == pattern {
+			c := *info
+			c.Disabled = status
+			t.routes[i] = &c
+			return
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package pure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+func TestUpdate(t *testing.T) {
+
+	p := New()
+	p.Get("/users/:id", idHandler)
+
+	hf := p.Serve()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// serve requests concurrently with the updates, run with -race
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				r, _ := http.NewRequest(http.MethodGet, "/users/13", nil)
+				w := httptest.NewRecorder()
+				hf.ServeHTTP(w, r)
+				if w.Code != http.StatusOK || w.Body.String() != "13" {
+					panic("unexpected response: " + strconv.Itoa(w.Code) + " " + w.Body.String())
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		p.Update(func(g IRouteGroup) {
+			g.Get("/flags/"+strconv.Itoa(i)+"/:p1/:p2", params2Handler)
+			g.Group("/items").Post("/"+strconv.Itoa(i), defaultHandler)
+		})
+	}
+	close(stop)
+	wg.Wait()
+
+	code, body := request(http.MethodGet, "/flags/49/a/b", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "a|b")
+
+	code, body = request(http.MethodPost, "/items/0", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, http.MethodPost)
+	Equal(t, len(p.Routes()), 101)
+
+	// a failed update leaves the routes unchanged
+	PanicMatches(t, func() {
+		p.Update(func(g IRouteGroup) {
+			g.Get("/partial", defaultHandler).Name("partial")
+			g.Get("/users/:user_id", defaultHandler)
+		})
+	}, "path segment ':user_id' conflicts with existing wildcard ':id' in path '/users/:user_id'")
+
+	code, _ = request(http.MethodGet, "/partial", p)
+	Equal(t, code, http.StatusNotFound)
+	Equal(t, len(p.Routes()), 101)
+	_, err := p.URL("partial")
+	Equal(t, err.Error(), "no route registered with name 'partial'")
+
+	// registering on existing groups and hosts within an update
+	api := p.Host("api.example.com")
+	p.Update(func(g IRouteGroup) {
+		api.Get("/users/:id", idHandler)
+		p.Host(":tenant.example.com").Get("/", defaultHandler)
+	})
+
+	code, body = request(http.MethodGet, "http://api.example.com/users/2", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "2")
+
+	code, body = request(http.MethodGet, "http://acme.example.com/", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, http.MethodGet)
+}
+
+func TestSwap(t *testing.T) {
+
+	p := New()
+	p.RegisterMethodNotAllowed()
+	p.Get("/old", defaultHandler)
+
+	hf := p.Serve()
+
+	next := New()
+	next.Get("/new/:id", idHandler).Name("new")
+	next.Post("/old", defaultHandler)
+
+	p.Swap(next)
+
+	r, _ := http.NewRequest(http.MethodGet, "/new/3", nil)
+	w := httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Body.String(), "3")
+
+	code, _ := request(http.MethodGet, "/old", p)
+	Equal(t, code, http.StatusMethodNotAllowed)
+
+	u, err := p.URL("new", "id", "4")
+	Equal(t, err, nil)
+	Equal(t, u, "/new/4")
+	Equal(t, len(p.Routes()), 2)
+}
+
+func TestRemove(t *testing.T) {
+
+	routes := []string{
+		"/",
+		"/users/new",
+		"/users/newest",
+		"/users/:id",
+		"/users/:id/profile",
+		"/users/:id/posts/*",
+		"/files/:name.:ext",
+		"/files/:name.json.:gz",
+		"/static/*path",
+		"/repos/:owner/*path/blob",
+		"/search",
+		"/support",
+	}
+
+	p := New()
+	for _, r := range routes {
+		p.Get(r, func(w http.ResponseWriter, r *http.Request) {
+			if _, err := w.Write([]byte(RequestVars(r).Pattern())); err != nil {
+				panic(err)
+			}
+		}).Name(r)
+	}
+	p.Post("/users/:id", defaultHandler)
+
+	Equal(t, p.Remove(http.MethodGet, "/users/unknown"), false)
+	Equal(t, p.Remove(http.MethodGet, "/users/:user_id"), false)
+	Equal(t, p.Remove(http.MethodGet, "/users/:id/posts"), false)
+	Equal(t, p.Remove(http.MethodPut, "/users/:id"), false)
+
+	tests := []struct {
+		url     string
+		pattern string
+	}{
+		{"/", "/"},
+		{"/users/new", "/users/new"},
+		{"/users/newest", "/users/newest"},
+		{"/users/newer", "/users/:id"},
+		{"/users/13", "/users/:id"},
+		{"/users/13/profile", "/users/:id/profile"},
+		{"/users/13/posts/a/b", "/users/:id/posts/*"},
+		{"/files/a.json.gz", "/files/:name.json.:gz"},
+		{"/files/a.txt", "/files/:name.:ext"},
+		{"/static/a/b", "/static/*path"},
+		{"/repos/me/a/b/blob", "/repos/:owner/*path/blob"},
+		{"/search", "/search"},
+		{"/support", "/support"},
+	}
+
+	// remove each route in turn ensuring the remaining routes still match
+	for i, r := range routes {
+		Equal(t, p.Remove(http.MethodGet, r), true)
+		Equal(t, p.Remove(http.MethodGet, r), false)
+
+		_, err := p.URL(r)
+		NotEqual(t, err, nil)
+
+		removed := routes[:i+1]
+
+		for _, tt := range tests {
+			code, body := request(http.MethodGet, tt.url, p)
+
+			var isRemoved bool
+			for _, rr := range removed {
+				if rr == tt.pattern {
+					isRemoved = true
+				}
+			}
+			if isRemoved {
+				NotEqual(t, body, tt.pattern)
+				continue
+			}
+			Equal(t, code, http.StatusOK)
+			Equal(t, body, tt.pattern)
+		}
+	}
+
+	_, ok := p.served().trees[http.MethodGet]
+	Equal(t, ok, false)
+	Equal(t, len(p.Routes()), 1)
+
+	code, body := request(http.MethodPost, "/users/13", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, http.MethodPost)
+
+	// removed nodes are pruned and merged, leaving the same tree as never having registered them
+	p = New()
+	p.Get("/users/new", defaultHandler)
+	p.Get("/users/:id", defaultHandler)
+	p.Get("/users/:id/profile", defaultHandler)
+	p.Remove(http.MethodGet, "/users/new")
+	p.Remove(http.MethodGet, "/users/:id/profile")
+
+	tree := p.served().trees[http.MethodGet]
+	Equal(t, tree.indices, "/")
+	Equal(t, tree.priority, uint32(1))
+	Equal(t, tree.children[0].path, "/users/")
+	Equal(t, tree.children[0].indices, "")
+	Equal(t, tree.children[0].priority, uint32(1))
+	Equal(t, tree.children[0].param.indices, "")
+	Equal(t, len(tree.children[0].param.children), 0)
+}
+
+func TestDisable(t *testing.T) {
+
+	p := New()
+	p.Use(func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Middleware", "true")
+			next(w, r)
+		}
+	})
+	p.Get("/users/:id", idHandler)
+	p.Get("/legacy", defaultHandler)
+
+	Equal(t, p.Disable(http.MethodGet, "/unknown", http.StatusGone), false)
+	Equal(t, p.Disable(http.MethodGet, "/legacy", http.StatusGone), true)
+	Equal(t, p.Disable(http.MethodGet, "/users/:id", http.StatusServiceUnavailable), true)
+
+	r, _ := http.NewRequest(http.MethodGet, "/legacy", nil)
+	w := httptest.NewRecorder()
+	p.Serve().ServeHTTP(w, r)
+	Equal(t, w.Code, http.StatusGone)
+	Equal(t, w.Header().Get("X-Middleware"), "true")
+
+	code, _ := request(http.MethodGet, "/users/13", p)
+	Equal(t, code, http.StatusServiceUnavailable)
+
+	routes := p.Routes()
+	Equal(t, routes[0].Disabled, http.StatusServiceUnavailable)
+	Equal(t, routes[1].Disabled, http.StatusGone)
+
+	Equal(t, p.Enable(http.MethodGet, "/users/:id"), true)
+	Equal(t, p.Enable(http.MethodGet, "/unknown"), false)
+
+	code, body := request(http.MethodGet, "/users/13", p)
+	Equal(t, code, http.StatusOK)
+	Equal(t, body, "13")
+	Equal(t, p.Routes()[0].Disabled, 0)
+
+	code, _ = request(http.MethodGet, "/legacy", p)
+	Equal(t, code, http.StatusGone)
+}
@@ -0,0 +1,180 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// DefaultTypedMaxBytes is the default maximum size of the request body decoded by typed handlers
+const DefaultTypedMaxBytes int64 = 10 << 20
+
+// TypedOption configures a typed handler created using Typed or TypedE
+type TypedOption func(o *typedOptions)
+
+type typedOptions struct {
+	maxBytes int64
+	status   int
+}
+
+// WithMaxBytes sets the maximum size of the request body decoded, default DefaultTypedMaxBytes
+func WithMaxBytes(maxBytes int64) TypedOption {
+	return func(o *typedOptions) {
+		o.maxBytes = maxBytes
+	}
+}
+
+// WithStatus sets the status code responded with on success, default http.StatusOK
+// eg. http.StatusCreated
+func WithStatus(status int) TypedOption {
+	return func(o *typedOptions) {
+		o.status = status
+	}
+}
+
+// Typed adapts the function to an http.HandlerFunc, decoding the request into Req using Decode,
+// including the query and SEO params, and encoding the Resp returned as JSON or XML based on
+// the Accept header, JSON being preferred.
+//
+// Errors are responded with like the default error handler, see RegisterErrorHandler; an
+// *HTTPError with it's status and public message, any other with 500 Internal Server Error.
+// Failing to decode the request responds with 400 Bad Request, an unsupported Content-Type
+// with 415 Unsupported Media Type and an unacceptable Accept header with 406 Not Acceptable.
+// Use TypedE for the errors to be handled by the Mux's error handler instead.
+//
+//	p.Post("/users", pure.Typed(func(ctx context.Context, req CreateUser) (User, error) {
+//		...
+//	}, pure.WithStatus(http.StatusCreated)))
+func Typed[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error), opts ...TypedOption) http.HandlerFunc {
+	h := TypedE(fn, opts...)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			defaultErrorHandler(w, r, err)
+		}
+	}
+}
+
+// TypedE is the same as Typed except it returns a HandlerFuncE, to be registered using the
+// E suffixed methods eg. PostE, the errors being handled by the Mux's error handler registered
+// using RegisterErrorHandler. Decoding and negotiation errors are returned as *HTTPError's.
+func TypedE[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error), opts ...TypedOption) HandlerFuncE {
+	o := &typedOptions{
+		maxBytes: DefaultTypedMaxBytes,
+		status:   http.StatusOK,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) error {
+		contentType := negotiate(r.Header.Get(httpext.Accept))
+		if contentType == blank {
+			return &HTTPError{Status: http.StatusNotAcceptable}
+		}
+
+		var req Req
+		if err := decodeTyped(r, o.maxBytes, &req); err != nil {
+			return err
+		}
+
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		if contentType == httpext.ApplicationXML {
+			return httpext.XML(w, o.status, resp)
+		}
+		return httpext.JSON(w, o.status, resp)
+	}
+}
+
+// decodeTyped decodes the request into v using Decode, returning *HTTPError's
+func decodeTyped(r *http.Request, maxBytes int64, v interface{}) error {
+	if r.ContentLength != 0 {
+		typ := r.Header.Get(httpext.ContentType)
+		if i := strings.IndexByte(typ, ';'); i != -1 {
+			typ = typ[:i]
+		}
+		switch strings.TrimSpace(typ) {
+		case "application/json", "application/xml", httpext.ApplicationForm, httpext.MultipartForm:
+		default:
+			return &HTTPError{Status: http.StatusUnsupportedMediaType}
+		}
+	}
+
+	// a missing body is left to validation of the decoded value
+	if err := Decode(r, httpext.QueryParams, maxBytes, v); err != nil && !errors.Is(err, io.EOF) {
+		return &HTTPError{Status: http.StatusBadRequest, Message: "invalid request", Err: err}
+	}
+	return nil
+}
+
+// negotiate returns the content type, JSON or XML, best matching the Accept header
+// JSON being preferred; blank when neither are acceptable.
+func negotiate(accept string) string {
+	if accept == blank {
+		return httpext.ApplicationJSON
+	}
+
+	// the quality of each, from the most specific media range matching it
+	var jsonQ, xmlQ float64
+	jsonSpecificity, xmlSpecificity := -1, -1
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, q := parseMediaRange(mediaRange)
+
+		switch mediaType {
+		case "application/json":
+			jsonQ, jsonSpecificity = q, 2
+		case "application/xml", "text/xml":
+			if xmlSpecificity < 2 || q > xmlQ {
+				xmlQ, xmlSpecificity = q, 2
+			}
+		case "application/*":
+			if jsonSpecificity < 1 {
+				jsonQ, jsonSpecificity = q, 1
+			}
+			if xmlSpecificity < 1 {
+				xmlQ, xmlSpecificity = q, 1
+			}
+		case "*/*":
+			if jsonSpecificity < 0 {
+				jsonQ, jsonSpecificity = q, 0
+			}
+			if xmlSpecificity < 0 {
+				xmlQ, xmlSpecificity = q, 0
+			}
+		}
+	}
+
+	switch {
+	case jsonQ > 0 && jsonQ >= xmlQ:
+		return httpext.ApplicationJSON
+	case xmlQ > 0:
+		return httpext.ApplicationXML
+	}
+	return blank
+}
+
+// parseMediaRange returns the lowercase media type and quality of the media range
+// eg. application/json;q=0.8
+func parseMediaRange(mediaRange string) (string, float64) {
+	params := strings.Split(mediaRange, ";")
+	q := 1.0
+
+	for _, param := range params[1:] {
+		param = strings.TrimSpace(param)
+		if len(param) > 2 && (param[0] == 'q' || param[0] == 'Q') && param[1] == '=' {
+			if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+				q = v
+			}
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(params[0])), q
+}
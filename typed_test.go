@@ -0,0 +1,114 @@
+package pure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// NOTES:
+// - Run "go test" to run tests
+// - Run "gocov test | gocov report" to report on test converage by file
+// - Run "gocov test | gocov annotate -" to report on all code and functions, those ,marked with "MISS" were never called
+//
+// or
+//
+// -- may be a good idea to change to output path to somewherelike /tmp
+// go test -coverprofile cover.out && go tool cover -html=cover.out -o cover.html
+//
+
+type typedRequest struct {
+	ID    int    `json:"-" form:"id"`
+	Name  string `json:"name" form:"name"`
+	Limit int    `json:"-" form:"limit"`
+}
+
+type typedResponse struct {
+	ID    int    `json:"id" xml:"id"`
+	Name  string `json:"name" xml:"name"`
+	Limit int    `json:"limit" xml:"limit"`
+}
+
+func typedHandler(ctx context.Context, req typedRequest) (typedResponse, error) {
+	switch req.Name {
+	case "forbidden":
+		return typedResponse{}, &HTTPError{Status: http.StatusForbidden, Message: "not allowed"}
+	case "fail":
+		return typedResponse{}, errors.New("internal details")
+	}
+	return typedResponse{ID: req.ID, Name: req.Name, Limit: req.Limit}, nil
+}
+
+func TestTyped(t *testing.T) {
+	p := New()
+	p.Get("/users/:id", Typed(typedHandler))
+	p.Put("/users/:id", Typed(typedHandler, WithStatus(http.StatusAccepted)))
+
+	tests := []struct {
+		method      string
+		path        string
+		contentType string
+		accept      string
+		body        string
+		code        int
+		expected    string
+	}{
+		{http.MethodGet, "/users/13?limit=5&name=joeybloggs", "", "", "", http.StatusOK, `{"id":13,"name":"joeybloggs","limit":5}`},
+		{http.MethodPut, "/users/13?limit=5", httpext.ApplicationJSON, "", `{"name":"joeybloggs"}`, http.StatusAccepted, `{"id":13,"name":"joeybloggs","limit":5}`},
+		{http.MethodPut, "/users/13", httpext.ApplicationForm, "", "name=joeybloggs", http.StatusAccepted, `{"id":13,"name":"joeybloggs","limit":0}`},
+		{http.MethodGet, "/users/13", "", "application/xml", "", http.StatusOK, `<typedResponse><id>13</id><name></name><limit>0</limit></typedResponse>`},
+		{http.MethodGet, "/users/13", "", "text/html, application/xml;q=0.9, */*;q=0.8", "", http.StatusOK, `<typedResponse><id>13</id><name></name><limit>0</limit></typedResponse>`},
+		{http.MethodGet, "/users/13", "", "application/xml;q=0.5, application/*", "", http.StatusOK, `{"id":13,"name":"","limit":0}`},
+		{http.MethodGet, "/users/13", "", "text/html", "", http.StatusNotAcceptable, "Not Acceptable\n"},
+		{http.MethodGet, "/users/13", "", "application/json;q=0", "", http.StatusNotAcceptable, "Not Acceptable\n"},
+		{http.MethodPut, "/users/13", httpext.ApplicationJSON, "", `{"name":`, http.StatusBadRequest, "invalid request\n"},
+		{http.MethodPut, "/users/13", "text/plain", "", "name", http.StatusUnsupportedMediaType, "Unsupported Media Type\n"},
+		{http.MethodGet, "/users/notanumber", "", "", "", http.StatusBadRequest, "invalid request\n"},
+		{http.MethodGet, "/users/13?name=forbidden", "", "", "", http.StatusForbidden, "not allowed\n"},
+		{http.MethodGet, "/users/13?name=fail", "", "", "", http.StatusInternalServerError, "Internal Server Error\n"},
+	}
+
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if tt.body == "" {
+			r, _ = http.NewRequest(tt.method, tt.path, nil)
+		}
+		if tt.contentType != "" {
+			r.Header.Set(httpext.ContentType, tt.contentType)
+		}
+		if tt.accept != "" {
+			r.Header.Set(httpext.Accept, tt.accept)
+		}
+		w := httptest.NewRecorder()
+		p.serveHTTP(w, r)
+		Equal(t, w.Code, tt.code)
+		Equal(t, strings.TrimPrefix(w.Body.String(), "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"), tt.expected)
+	}
+}
+
+func TestTypedE(t *testing.T) {
+	var handled error
+
+	p := New()
+	p.RegisterErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(http.StatusTeapot)
+	})
+	p.PostE("/users/:id", TypedE(typedHandler, WithMaxBytes(16)))
+
+	r, _ := http.NewRequest(http.MethodPost, "/users/13", strings.NewReader(`{"name":"joeybloggs"}`))
+	r.Header.Set(httpext.ContentType, httpext.ApplicationJSON)
+	w := httptest.NewRecorder()
+	p.serveHTTP(w, r)
+	Equal(t, w.Code, http.StatusTeapot)
+
+	var e *HTTPError
+	Equal(t, errors.As(handled, &e), true)
+	Equal(t, e.Status, http.StatusBadRequest)
+}
@@ -1,5 +1,12 @@
 package pure
 
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
 func min(a, b int) int {
 	if a <= b {
 		return a
@@ -7,15 +14,229 @@ func min(a, b int) int {
 	return b
 }
 
-func countParams(path string) uint8 {
-	var n uint // add one just as a buffer
+func countParams(path string) int {
+	var n int
 	for i := 0; i < len(path); i++ {
 		if path[i] == paramByte || path[i] == wildByte {
 			n++
+			i = wildcardEnd(path, i) - 1
+		}
+	}
+	return n
+}
+
+// isNameByte returns if the byte is valid within a param name
+func isNameByte(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c|0x20 >= 'a' && c|0x20 <= 'z')
+}
+
+// wildcardEnd returns the end of the wildcard starting at i, the wildcard being
+// it's name followed by an optional constraint eg. :id<int>. When a param is the
+// only wildcard in it's path segment it's name runs up to the next '/' or constraint,
+// eg. /:user-id, otherwise names end at the first byte not valid within a name so
+// wildcards can be separated by static literals eg. /:name.:ext
+func wildcardEnd(path string, i int) int {
+	end := i + 1
+	if path[i] == paramByte && soleWildcard(path, i) {
+		for end < len(path) && path[end] != slashByte && path[end] != constraintStartByte {
+			end++
+		}
+	} else {
+		for end < len(path) && isNameByte(path[end]) {
+			end++
+		}
+	}
+	if end < len(path) && path[end] == constraintStartByte {
+		if e := skipConstraint(path, end); e != -1 {
+			return e
+		}
+	}
+	return end
+}
+
+// soleWildcard returns if the wildcard starting at i is the only one in it's path
+// segment. Host patterns have no path segments, their wildcards being separated by
+// static literals eg. :tenant.example.com
+func soleWildcard(path string, i int) bool {
+	j := i - 1
+	for ; j >= 0 && path[j] != slashByte; j-- {
+		if path[j] == paramByte || path[j] == wildByte {
+			return false
+		}
+	}
+	if j < 0 {
+		return false
+	}
+
+	for j = i + 1; j < len(path) && path[j] != slashByte; j++ {
+		switch path[j] {
+		case constraintStartByte:
+			// the constraint may contain any byte eg. <(?:jpg|png)>
+			if j = skipConstraint(path, j); j == -1 {
+				return true
+			}
+			j--
+		case paramByte, wildByte:
+			return false
+		}
+	}
+	return true
+}
+
+// catchAllKey returns the param key for the catch-all with the given name,
+// an unnamed catch-all, or one named wildcard, uses WildcardParam
+func catchAllKey(name string) string {
+	if name == blank || name == WildcardParam[1:] {
+		return WildcardParam
+	}
+	return name
+}
+
+// skipConstraint returns the index after the closing '>' of the constraint
+// starting at i, or -1 if the constraint is not terminated
+func skipConstraint(path string, i int) int {
+	var depth int
+	for ; i < len(path); i++ {
+		switch path[i] {
+		case constraintStartByte:
+			depth++
+		case constraintEndByte:
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// splitParam splits the param, without it's leading ':', into it's name and constraint
+// eg. id<int> returns id and int
+func splitParam(param string) (name string, constraint string) {
+	if i := strings.IndexByte(param, constraintStartByte); i != -1 && param[len(param)-1] == constraintEndByte {
+		return param[:i], param[i+1 : len(param)-1]
+	}
+	return param, blank
+}
+
+// unescape query unescapes the path, leaving the wildcards untouched so
+// param constraints such as <[a-z]+> remain intact.
+func unescape(path string) (string, error) {
+	var sb strings.Builder
+	var start int
+
+	for i := 0; i < len(path); i++ {
+		if path[i] != paramByte && path[i] != wildByte {
+			continue
+		}
+		s, err := url.QueryUnescape(path[start:i])
+		if err != nil {
+			return blank, err
+		}
+		end := wildcardEnd(path, i)
+		sb.WriteString(s)
+		sb.WriteString(path[i:end])
+		start = end
+		i = end - 1
+	}
+	if start == 0 {
+		return url.QueryUnescape(path)
+	}
+	s, err := url.QueryUnescape(path[start:])
+	if err != nil {
+		return blank, err
+	}
+	sb.WriteString(s)
+	return sb.String(), nil
+}
+
+// rawIndex returns the index within the escaped raw path corresponding to
+// the index i within its unescaped path
+func rawIndex(raw string, i int) int {
+	j := 0
+	for ; i > 0 && j < len(raw); i-- {
+		if raw[j] == '%' {
+			j += 3
+			continue
 		}
+		j++
+	}
+	return j
+}
+
+// cleanPath returns the canonical path, resolving '.' and '..' elements and removing
+// repeated slashes, keeping a single trailing slash if the path had one
+func cleanPath(p string) string {
+	if p == blank {
+		return basePath
+	}
+	if p[0] != slashByte {
+		p = basePath + p
 	}
-	if n >= 255 {
-		panic("too many parameters defined in path, max is 255")
+	cp := path.Clean(p)
+	if p[len(p)-1] == slashByte && cp != basePath {
+		cp += basePath
+	}
+	return cp
+}
+
+// unescapeParam returns the unescaped param value matched against the escaped path,
+// or the value as is if it can't be unescaped
+func unescapeParam(value string) string {
+	if strings.IndexByte(value, '%') == -1 {
+		return value
+	}
+	if v, err := url.PathUnescape(value); err == nil {
+		return v
+	}
+	return value
+}
+
+// hasMethod returns if the sorted methods contain the method
+func hasMethod(methods []string, method string) bool {
+	i := sort.SearchStrings(methods, method)
+	return i < len(methods) && methods[i] == method
+}
+
+// insertMethod returns a copy of the sorted methods with the method inserted
+// in order, or the methods unchanged if already present
+func insertMethod(methods []string, method string) []string {
+	if hasMethod(methods, method) {
+		return methods
+	}
+	i := sort.SearchStrings(methods, method)
+	c := make([]string, 0, len(methods)+1)
+	c = append(c, methods[:i]...)
+	c = append(c, method)
+	return append(c, methods[i:]...)
+}
+
+// mergeMethods returns the union of the sorted methods, sorted
+func mergeMethods(a []string, b []string) []string {
+	c := make([]string, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] == b[0]:
+			c = append(c, a[0])
+			a, b = a[1:], b[1:]
+		case a[0] < b[0]:
+			c = append(c, a[0])
+			a = a[1:]
+		default:
+			c = append(c, b[0])
+			b = b[1:]
+		}
+	}
+	c = append(c, a...)
+	return append(c, b...)
+}
+
+// removeMethod returns a copy of the sorted methods with the method removed
+func removeMethod(methods []string, method string) []string {
+	c := make([]string, 0, len(methods))
+	for _, m := range methods {
+		if m != method {
+			c = append(c, m)
+		}
 	}
-	return uint8(n)
+	return c
 }
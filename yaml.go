@@ -0,0 +1,125 @@
+package pure
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// yamlPlain matches the strings safe to write unquoted, those that could
+// be mistaken for another type eg. numbers and booleans are quoted
+var yamlPlain = regexp.MustCompile(`^[A-Za-z/][A-Za-z0-9_./{}$-]*$`)
+
+// jsonToYAML converts the JSON document to YAML in block style, object keys being sorted
+func jsonToYAML(b []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if isYAMLBlock(v) {
+		writeYAML(&buf, v, 0)
+	} else {
+		buf.WriteString(yamlScalar(v))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// writeYAML writes the non-empty object or array as a block at the indent
+func writeYAML(buf *bytes.Buffer, v interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			buf.WriteString(pad)
+			buf.WriteString(yamlString(k))
+			buf.WriteByte(':')
+
+			if isYAMLBlock(v[k]) {
+				buf.WriteByte('\n')
+				writeYAML(buf, v[k], indent+2)
+				continue
+			}
+			buf.WriteByte(' ')
+			buf.WriteString(yamlScalar(v[k]))
+			buf.WriteByte('\n')
+		}
+
+	case []interface{}:
+		for _, item := range v {
+			if !isYAMLBlock(item) {
+				buf.WriteString(pad)
+				buf.WriteString("- ")
+				buf.WriteString(yamlScalar(item))
+				buf.WriteByte('\n')
+				continue
+			}
+
+			// the item's block is written indented beneath the dash,
+			// it's first line is then moved up alongside the dash
+			var block bytes.Buffer
+			writeYAML(&block, item, indent+2)
+
+			buf.WriteString(pad)
+			buf.WriteString("- ")
+			buf.Write(block.Bytes()[indent+2:])
+		}
+	}
+}
+
+// isYAMLBlock returns if the value is a non-empty object or array
+func isYAMLBlock(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
+
+// yamlScalar returns the YAML of the scalar value, or empty object or array
+func yamlScalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return "null"
+}
+
+// yamlString returns the string unquoted when safe, otherwise double quoted
+func yamlString(s string) string {
+	if yamlPlain.MatchString(s) {
+		switch strings.ToLower(s) {
+		case "y", "n", "yes", "no", "on", "off", "true", "false", "null":
+		default:
+			return s
+		}
+	}
+	return strconv.Quote(s)
+}